@@ -59,8 +59,25 @@ type CapsuleOps struct {
 	// RawEquals to return true when given those values. If a given type
 	// does not uphold that assumption then sets including this type will
 	// not behave correctly.
+	//
+	// HashValue also uses the result of HashKey to encode capsule values, so
+	// checksums of values of the corresponding type are stable across
+	// processes only if the result of HashKey is too.
 	HashKey func(v any) string
 
+	// Compare provides the ordering of values of the corresponding type for
+	// the Compare function. This is called only with known, non-null values
+	// of the corresponding type, and must return a negative number if a
+	// sorts before b, a positive number if a sorts after b, or zero if the
+	// two are equivalent for ordering purposes.
+	//
+	// The result must define a total order, and should return zero for any
+	// two values that would cause RawEquals to return true.
+	//
+	// If Compare is nil, the Compare function will panic when given values
+	// of the corresponding type.
+	Compare func(a, b any) int
+
 	// ConversionFrom can provide conversions from the corresponding type to
 	// some other type when values of the corresponding type are used with
 	// the "convert" package. (The main cty package does not use this operation.)
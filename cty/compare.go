@@ -0,0 +1,178 @@
+package cty
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Compare defines a total order over values of the same type, returning
+// a negative number if a sorts before b, a positive number if a sorts after
+// b, or zero if the two values are equivalent for ordering purposes.
+//
+// Unlike Value.LessThan, which is an operation in the cty type system that
+// supports only numbers, Compare is an integration helper for calling
+// applications that need a deterministic order for values of any type, such
+// as for sorting a list of objects or producing stable output.
+//
+// Values are ordered as follows:
+//
+//   - Null values sort before all non-null values, and unknown values sort
+//     after all known values. Two null values or two unknown values of the
+//     same type are equivalent.
+//   - For bool, false sorts before true.
+//   - Numbers are ordered numerically, with numbers that are equal as
+//     defined by Value.RawEquals being equivalent even if they have
+//     different precisions.
+//   - Strings are ordered lexically by their normalized UTF-8 bytes.
+//   - Lists and tuples are ordered lexically by their elements, with a list
+//     that is a prefix of another sorting before it.
+//   - Sets are ordered as for lists after sorting the elements of each set
+//     using this same order.
+//   - Maps are ordered lexically by their key/value pairs in order of key,
+//     with each key compared before its corresponding value.
+//   - Objects are ordered lexically by the values of their attributes, in
+//     the lexical order of the attribute names.
+//   - Capsule values are ordered by the Compare capsule operation.
+//
+// Any marks on the given values, or on nested values, are ignored.
+//
+// Compare will panic if the two values do not have the same type, or if
+// they contain capsule values whose type does not implement the Compare
+// capsule operation.
+func Compare(a, b Value) int {
+	a, _ = a.UnmarkDeep()
+	b, _ = b.UnmarkDeep()
+	if !a.Type().Equals(b.Type()) {
+		panic(fmt.Sprintf("cannot compare %s with %s", a.Type().FriendlyName(), b.Type().FriendlyName()))
+	}
+	return compareValues(a, b)
+}
+
+// compareValues is the recursive implementation of Compare, which expects
+// to be given unmarked values of the same type.
+func compareValues(a, b Value) int {
+	switch {
+	case !a.IsKnown() || !b.IsKnown():
+		return compareBools(!a.IsKnown(), !b.IsKnown())
+	case a.IsNull() || b.IsNull():
+		return compareBools(!a.IsNull(), !b.IsNull())
+	}
+
+	ty := a.Type()
+	switch {
+	case ty == Bool:
+		return compareBools(a.True(), b.True())
+	case ty == Number:
+		return compareNumbers(a.AsBigFloat(), b.AsBigFloat())
+	case ty == String:
+		return strings.Compare(a.AsString(), b.AsString())
+	case ty.IsListType() || ty.IsTupleType():
+		return compareValueSlices(a.AsValueSlice(), b.AsValueSlice())
+	case ty.IsSetType():
+		as := a.AsValueSlice()
+		bs := b.AsValueSlice()
+		sortValues(as)
+		sortValues(bs)
+		return compareValueSlices(as, bs)
+	case ty.IsMapType():
+		am := a.AsValueMap()
+		bm := b.AsValueMap()
+		ak := sortedKeys(am)
+		bk := sortedKeys(bm)
+		for i := 0; i < len(ak) && i < len(bk); i++ {
+			if c := strings.Compare(ak[i], bk[i]); c != 0 {
+				return c
+			}
+			if c := compareValues(am[ak[i]], bm[bk[i]]); c != 0 {
+				return c
+			}
+		}
+		return len(ak) - len(bk)
+	case ty.IsObjectType():
+		names := make([]string, 0, len(ty.AttributeTypes()))
+		for name := range ty.AttributeTypes() {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if c := compareValues(a.GetAttr(name), b.GetAttr(name)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case ty.IsCapsuleType():
+		ops := ty.CapsuleOps()
+		if ops.Compare == nil {
+			panic(fmt.Sprintf("cannot compare values of type %s, because it does not implement the Compare capsule operation", ty.FriendlyName()))
+		}
+		return ops.Compare(a.EncapsulatedValue(), b.EncapsulatedValue())
+	default:
+		// Should never happen, since above should be exhaustive
+		panic(fmt.Sprintf("unsupported type %#v in Compare", ty))
+	}
+}
+
+// compareNumbers orders numbers consistently with the way RawEquals compares
+// them, and so returns zero only if rawNumberEqual would return true.
+//
+// rawNumberEqual considers two numbers that aren't integers to be equal if
+// they have the same shortest decimal representation at their respective
+// precisions, so numbers that differ only in their precision, such as 0.1
+// parsed from a string and 0.1 converted from a float64, are equal even
+// though big.Float.Cmp would order them. We therefore order finite numbers
+// by the exact values of those decimal representations, which is a total
+// order that agrees with rawNumberEqual.
+func compareNumbers(a, b *big.Float) int {
+	if rawNumberEqual(a, b) {
+		return 0
+	}
+	if a.IsInf() || b.IsInf() {
+		return a.Cmp(b)
+	}
+	ar, _ := new(big.Rat).SetString(a.Text('f', -1))
+	br, _ := new(big.Rat).SetString(b.Text('f', -1))
+	if c := ar.Cmp(br); c != 0 {
+		return c
+	}
+	// Should never happen, because equal decimal representations would have
+	// been equal to rawNumberEqual too, but we'll keep the order total
+	// regardless.
+	return strings.Compare(a.Text('f', -1), b.Text('f', -1))
+}
+
+func compareBools(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func compareValueSlices(as, bs []Value) int {
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareValues(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return len(as) - len(bs)
+}
+
+func sortValues(vals []Value) {
+	sort.SliceStable(vals, func(i, j int) bool {
+		return compareValues(vals[i], vals[j]) < 0
+	})
+}
+
+func sortedKeys(m map[string]Value) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
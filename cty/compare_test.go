@@ -0,0 +1,229 @@
+package cty
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	orderedCapsule := CapsuleWithOps("ordered", reflect.TypeOf(0), &CapsuleOps{
+		Compare: func(a, b any) int {
+			return *a.(*int) - *b.(*int)
+		},
+	})
+	one, two := 1, 2
+
+	// Each of the following sequences is in ascending order, so each value
+	// must compare less than all of the values after it.
+	tests := map[string][]Value{
+		"bool": {
+			NullVal(Bool),
+			False,
+			True,
+			UnknownVal(Bool),
+		},
+		"number": {
+			NullVal(Number),
+			NegativeInfinity,
+			NumberIntVal(-5),
+			Zero,
+			NumberFloatVal(0.5),
+			NumberIntVal(10),
+			PositiveInfinity,
+			UnknownVal(Number).RefineNotNull(),
+		},
+		"string": {
+			NullVal(String),
+			StringVal(""),
+			StringVal("A"),
+			StringVal("a"),
+			StringVal("ab"),
+			StringVal("b"),
+			UnknownVal(String),
+		},
+		"list": {
+			NullVal(List(String)),
+			ListValEmpty(String),
+			ListVal([]Value{NullVal(String)}),
+			ListVal([]Value{StringVal("a")}),
+			ListVal([]Value{StringVal("a"), StringVal("a")}),
+			ListVal([]Value{StringVal("a"), UnknownVal(String)}),
+			ListVal([]Value{StringVal("b")}),
+			UnknownVal(List(String)),
+		},
+		"set": {
+			SetValEmpty(Number),
+			SetVal([]Value{NumberIntVal(3), NumberIntVal(1)}),
+			SetVal([]Value{NumberIntVal(1), NumberIntVal(4)}),
+			SetVal([]Value{NumberIntVal(2)}),
+		},
+		"map": {
+			MapValEmpty(Number),
+			MapVal(map[string]Value{"a": NumberIntVal(2)}),
+			MapVal(map[string]Value{"a": NumberIntVal(2), "b": NumberIntVal(0)}),
+			MapVal(map[string]Value{"a": NumberIntVal(3)}),
+			MapVal(map[string]Value{"b": NumberIntVal(0)}),
+		},
+		"tuple": {
+			TupleVal([]Value{StringVal("a"), NumberIntVal(2)}),
+			TupleVal([]Value{StringVal("a"), NumberIntVal(3)}),
+			TupleVal([]Value{StringVal("b"), NumberIntVal(1)}),
+		},
+		"object": {
+			ObjectVal(map[string]Value{"b": StringVal("z"), "a": NumberIntVal(1)}),
+			ObjectVal(map[string]Value{"b": StringVal("a"), "a": NumberIntVal(2)}),
+			ObjectVal(map[string]Value{"b": StringVal("b"), "a": NumberIntVal(2)}),
+		},
+		"capsule": {
+			NullVal(orderedCapsule),
+			CapsuleVal(orderedCapsule, &one),
+			CapsuleVal(orderedCapsule, &two),
+		},
+	}
+
+	for name, vals := range tests {
+		t.Run(name, func(t *testing.T) {
+			for i, a := range vals {
+				for j, b := range vals {
+					got := Compare(a, b)
+					switch {
+					case i < j && got >= 0:
+						t.Errorf("Compare(%#v, %#v) = %d, but want negative", a, b, got)
+					case i > j && got <= 0:
+						t.Errorf("Compare(%#v, %#v) = %d, but want positive", a, b, got)
+					case i == j && got != 0:
+						t.Errorf("Compare(%#v, %#v) = %d, but want zero", a, b, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCompareEquivalent(t *testing.T) {
+	tests := []struct {
+		A, B Value
+	}{
+		{
+			NumberIntVal(1),
+			NumberFloatVal(1.0),
+		},
+		{
+			NumberFloatVal(0.1),
+			MustParseNumberVal("0.1"),
+		},
+		{
+			SetVal([]Value{NumberFloatVal(0.1), NumberIntVal(2)}),
+			SetVal([]Value{MustParseNumberVal("0.1"), NumberIntVal(2)}),
+		},
+		{
+			StringVal("a").Mark("sensitive"),
+			StringVal("a"),
+		},
+		{
+			ListVal([]Value{StringVal("a").Mark("sensitive")}),
+			ListVal([]Value{StringVal("a")}),
+		},
+		{
+			UnknownVal(String),
+			UnknownVal(String).Refine().StringPrefix("a").NewValue(),
+		},
+		{
+			SetVal([]Value{StringVal("a"), StringVal("b")}),
+			SetVal([]Value{StringVal("b"), StringVal("a")}),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v and %#v", test.A, test.B), func(t *testing.T) {
+			if got := Compare(test.A, test.B); got != 0 {
+				t.Errorf("wrong result %d; want zero", got)
+			}
+		})
+	}
+}
+
+func TestCompareNumbersMatchesRawEquals(t *testing.T) {
+	// Numbers with different precisions may or may not be equal as defined
+	// by RawEquals, and Compare must agree with it in either case.
+	highPrec := NumberVal(new(big.Float).SetPrec(512).SetFloat64(0.1))
+	vals := []Value{
+		NegativeInfinity,
+		NumberIntVal(-1),
+		MustParseNumberVal("-0.1"),
+		NumberFloatVal(-0.1),
+		NumberIntVal(0),
+		NumberFloatVal(math.Copysign(0, -1)),
+		MustParseNumberVal("0.09999999999999999"),
+		NumberFloatVal(0.1),
+		MustParseNumberVal("0.1"),
+		highPrec,
+		MustParseNumberVal("0.1000000000000000055511151231257827021181583404541015626"),
+		NumberFloatVal(1.0 / 3),
+		MustParseNumberVal("0.3333333333333333"),
+		NumberIntVal(1),
+		MustParseNumberVal("1.0"),
+		PositiveInfinity,
+	}
+
+	for _, a := range vals {
+		for _, b := range vals {
+			got := Compare(a, b)
+			if (got == 0) != a.RawEquals(b) {
+				t.Errorf("Compare(%#v, %#v) = %d, but RawEquals is %t", a, b, got, a.RawEquals(b))
+			}
+			if rev := Compare(b, a); (got < 0) != (rev > 0) {
+				t.Errorf("Compare(%#v, %#v) = %d, but reversed is %d", a, b, got, rev)
+			}
+			for _, c := range vals {
+				if got <= 0 && Compare(b, c) <= 0 && Compare(a, c) > 0 {
+					t.Errorf("order is not transitive for %#v, %#v, %#v", a, b, c)
+				}
+			}
+		}
+	}
+
+	// The float64 nearest to 0.1 is slightly larger than 0.1, and so its
+	// full-precision representation sorts after the shorter one.
+	if got := Compare(NumberFloatVal(0.1), highPrec); got >= 0 {
+		t.Errorf("wrong result %d; want negative", got)
+	}
+}
+
+func TestComparePanics(t *testing.T) {
+	unordered := Capsule("unordered", reflect.TypeOf(0))
+	i := 0
+	tests := map[string]struct {
+		A, B Value
+		Want string
+	}{
+		"different types": {
+			StringVal("a"),
+			NumberIntVal(1),
+			"cannot compare string with number",
+		},
+		"capsule without Compare": {
+			CapsuleVal(unordered, &i),
+			CapsuleVal(unordered, &i),
+			"cannot compare values of type unordered, because it does not implement the Compare capsule operation",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					t.Fatalf("Compare did not panic")
+				}
+				if got := fmt.Sprint(r); got != test.Want {
+					t.Errorf("wrong panic message\ngot:  %s\nwant: %s", got, test.Want)
+				}
+			}()
+			Compare(test.A, test.B)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package convert
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// AssignabilityClass categorizes whether values of one type can be used where
+// another type is expected. The classes are ordered so that a greater class
+// is always a better outcome than a lesser one.
+type AssignabilityClass int
+
+const (
+	// AssignImpossible means that there is no conversion between the types,
+	// so no value of the given type can be used where the wanted type is
+	// expected.
+	AssignImpossible AssignabilityClass = iota
+
+	// AssignUnsafe means that there is only an unsafe conversion between the
+	// types, which may fail for some values of the given type.
+	AssignUnsafe
+
+	// AssignSafe means that there is a safe conversion between the types,
+	// which will succeed for any value of the given type.
+	AssignSafe
+
+	// AssignIdentical means that the types are the same, so values of the
+	// given type can be used where the wanted type is expected without any
+	// conversion at all.
+	AssignIdentical
+)
+
+func (c AssignabilityClass) String() string {
+	switch c {
+	case AssignImpossible:
+		return "impossible"
+	case AssignUnsafe:
+		return "unsafe"
+	case AssignSafe:
+		return "safe"
+	case AssignIdentical:
+		return "identical"
+	default:
+		return fmt.Sprintf("AssignabilityClass(%d)", int(c))
+	}
+}
+
+// Assignability is the result of CheckAssignability.
+type Assignability struct {
+	Class AssignabilityClass
+
+	// UnsafePaths are the locations within the given type that make the
+	// conversion unsafe, when Class is AssignUnsafe. They use the same
+	// conventions as cty.WalkType: collection elements are represented by an
+	// IndexStep with an unknown key and tuple elements are represented by an
+	// IndexStep with a known number key. The attributes of an object type
+	// being converted to a map type are represented by an IndexStep with a
+	// known string key, matching the corresponding map element.
+	//
+	// A path refers to the deepest type that can be blamed for the
+	// unsafety, which may be the given type itself if the conversion between
+	// the kinds of type is inherently unsafe, such as from a list to a set.
+	UnsafePaths []cty.Path
+}
+
+// CheckAssignability classifies whether values of type "in" can be used
+// where type "out" is expected, without needing to construct and call a
+// conversion.
+//
+// The classification is consistent with GetConversion and
+// GetConversionUnsafe: the result is AssignSafe or AssignIdentical if
+// GetConversion would return a conversion, and AssignUnsafe if only
+// GetConversionUnsafe would.
+func CheckAssignability(in, out cty.Type) Assignability {
+	return defaultConverter.checkAssignability(in, out)
+}
+
+// CheckAssignabilityWithOptions is like CheckAssignability except that it
+// considers only the conversions allowed by the given options.
+func CheckAssignabilityWithOptions(in, out cty.Type, opts Options) Assignability {
+	c := &converter{opts: opts}
+	return c.checkAssignability(in, out)
+}
+
+func (c *converter) checkAssignability(in, out cty.Type) Assignability {
+	switch {
+	case in.Equals(out) || in.Equals(out.WithoutOptionalAttributesDeep()):
+		// This matches the shortcut in Convert, which returns values of
+		// this type unchanged.
+		return Assignability{Class: AssignIdentical}
+	case c.getConversion(in, out, false) != nil:
+		return Assignability{Class: AssignSafe}
+	case c.getConversion(in, out, true) != nil:
+		paths := c.unsafePaths(nil, in, out, nil)
+		sort.Slice(paths, func(i, j int) bool {
+			return comparePaths(paths[i], paths[j]) < 0
+		})
+		return Assignability{Class: AssignUnsafe, UnsafePaths: paths}
+	default:
+		return Assignability{Class: AssignImpossible}
+	}
+}
+
+// unsafePaths appends to ret the paths that make the conversion from in to
+// out unsafe, assuming that an unsafe conversion exists.
+//
+// This follows the structure of getConversionKnown, looking for the deepest
+// nested types that can't be converted safely. If it finds that a kind of
+// conversion is inherently unsafe, or can't find any nested type to blame,
+// then it blames the given types themselves.
+func (c *converter) unsafePaths(path cty.Path, in, out cty.Type, ret []cty.Path) []cty.Path {
+	if c.getConversion(in, out, false) != nil {
+		return ret
+	}
+	before := len(ret)
+	self := false
+
+	// nested checks a pair of nested types, extending the current path
+	// with the given step.
+	nested := func(step cty.PathStep, in, out cty.Type) {
+		if in.Equals(out) {
+			return
+		}
+		path := append(path.Copy(), step)
+		ret = c.unsafePaths(path, in, out, ret)
+	}
+
+	switch {
+	case out.IsObjectType() && in.IsObjectType():
+		inAtys := in.AttributeTypes()
+		for _, name := range sortedAttributeNames(out.AttributeTypes()) {
+			if inAty, ok := inAtys[name]; ok {
+				nested(cty.GetAttrStep{Name: name}, inAty, out.AttributeType(name))
+			}
+		}
+
+	case out.IsTupleType() && in.IsTupleType():
+		outEtys := out.TupleElementTypes()
+		for i, inEty := range in.TupleElementTypes() {
+			nested(cty.IndexStep{Key: cty.NumberIntVal(int64(i))}, inEty, outEtys[i])
+		}
+
+	case out.IsCollectionType() && in.IsCollectionType():
+		if out.IsSetType() && in.IsListType() {
+			// Converting a list to a set loses the element order and
+			// any duplicate elements, regardless of the element types.
+			self = true
+		}
+		nested(cty.CollectionElementStep(in), in.ElementType(), out.ElementType())
+
+	case (out.IsListType() || out.IsSetType()) && in.IsTupleType():
+		for i, inEty := range in.TupleElementTypes() {
+			nested(cty.IndexStep{Key: cty.NumberIntVal(int64(i))}, inEty, out.ElementType())
+		}
+
+	case out.IsMapType() && in.IsObjectType():
+		atys := in.AttributeTypes()
+		for _, name := range sortedAttributeNames(atys) {
+			nested(cty.IndexStep{Key: cty.StringVal(name)}, atys[name], out.ElementType())
+		}
+
+	case out.IsObjectType() && in.IsMapType():
+		// A map may not have elements for all of the required attributes.
+		for name := range out.AttributeTypes() {
+			if !out.AttributeOptional(name) {
+				self = true
+				break
+			}
+		}
+		for _, name := range sortedAttributeNames(out.AttributeTypes()) {
+			nested(cty.CollectionElementStep(in), in.ElementType(), out.AttributeType(name))
+		}
+		ret = dedupePaths(ret, before)
+
+	default:
+		// Any other unsafe conversion, such as between primitive types or
+		// from cty.DynamicPseudoType, is unsafe only because of the types
+		// themselves.
+		self = true
+	}
+
+	if self || len(ret) == before {
+		ret = append(ret, path.Copy())
+	}
+	return ret
+}
+
+// dedupePaths removes any duplicate paths from ret after the given index,
+// which can arise when several types are compared against the same nested
+// type.
+func dedupePaths(ret []cty.Path, from int) []cty.Path {
+	out := ret[:from]
+Paths:
+	for _, path := range ret[from:] {
+		for _, existing := range out[from:] {
+			if existing.Equals(path) {
+				continue Paths
+			}
+		}
+		out = append(out, path)
+	}
+	return out
+}
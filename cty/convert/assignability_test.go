@@ -0,0 +1,190 @@
+package convert
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCheckAssignability(t *testing.T) {
+	tests := []struct {
+		In, Out   cty.Type
+		Opts      *Options
+		WantClass AssignabilityClass
+		WantPaths []string
+	}{
+		{
+			In:        cty.String,
+			Out:       cty.String,
+			WantClass: AssignIdentical,
+		},
+		{
+			In:        cty.Number,
+			Out:       cty.String,
+			WantClass: AssignSafe,
+		},
+		{
+			In:        cty.String,
+			Out:       cty.Number,
+			WantClass: AssignUnsafe,
+			WantPaths: []string{""},
+		},
+		{
+			In:        cty.String,
+			Out:       cty.Number,
+			Opts:      &Strict,
+			WantClass: AssignImpossible,
+		},
+		{
+			In:        cty.Bool,
+			Out:       cty.Number,
+			WantClass: AssignImpossible,
+		},
+		{
+			In:        cty.DynamicPseudoType,
+			Out:       cty.String,
+			WantClass: AssignUnsafe,
+			WantPaths: []string{""},
+		},
+		{
+			In:        cty.String,
+			Out:       cty.DynamicPseudoType,
+			WantClass: AssignSafe,
+		},
+		{
+			In:        cty.List(cty.String),
+			Out:       cty.Set(cty.String),
+			WantClass: AssignUnsafe,
+			WantPaths: []string{""},
+		},
+		{
+			In:        cty.Set(cty.String),
+			Out:       cty.List(cty.String),
+			WantClass: AssignSafe,
+		},
+		{
+			In:        cty.List(cty.String),
+			Out:       cty.List(cty.Number),
+			WantClass: AssignUnsafe,
+			WantPaths: []string{"[*]"},
+		},
+		{
+			In:        cty.List(cty.String),
+			Out:       cty.Set(cty.Number),
+			WantClass: AssignUnsafe,
+			WantPaths: []string{"", "[*]"},
+		},
+		{
+			In: cty.Object(map[string]cty.Type{
+				"a": cty.String,
+				"b": cty.Number,
+				"c": cty.Bool,
+			}),
+			Out: cty.Object(map[string]cty.Type{
+				"a": cty.Number,
+				"b": cty.Number,
+				"c": cty.String,
+			}),
+			WantClass: AssignUnsafe,
+			WantPaths: []string{"a"},
+		},
+		{
+			In: cty.Object(map[string]cty.Type{
+				"a": cty.String,
+			}),
+			Out: cty.Object(map[string]cty.Type{
+				"a": cty.Bool,
+			}),
+			WantClass: AssignUnsafe,
+			WantPaths: []string{"a"},
+		},
+		{
+			In: cty.Object(map[string]cty.Type{
+				"a": cty.Bool,
+			}),
+			Out: cty.Object(map[string]cty.Type{
+				"a": cty.Number,
+			}),
+			WantClass: AssignImpossible,
+		},
+		{
+			In: cty.Object(map[string]cty.Type{
+				"a": cty.String,
+			}),
+			Out: cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"a": cty.String,
+			}, []string{"a"}),
+			WantClass: AssignIdentical,
+		},
+		{
+			In: cty.Object(map[string]cty.Type{
+				"a": cty.List(cty.Object(map[string]cty.Type{
+					"b": cty.String,
+				})),
+			}),
+			Out: cty.Object(map[string]cty.Type{
+				"a": cty.List(cty.Object(map[string]cty.Type{
+					"b": cty.Number,
+				})),
+			}),
+			WantClass: AssignUnsafe,
+			WantPaths: []string{"a[*].b"},
+		},
+		{
+			In: cty.Map(cty.String),
+			Out: cty.Object(map[string]cty.Type{
+				"a": cty.String,
+			}),
+			WantClass: AssignUnsafe,
+			WantPaths: []string{""},
+		},
+		{
+			In: cty.Map(cty.String),
+			Out: cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"a": cty.Number,
+				"b": cty.Number,
+			}, []string{"a", "b"}),
+			WantClass: AssignUnsafe,
+			WantPaths: []string{"[*]"},
+		},
+		{
+			In: cty.Object(map[string]cty.Type{
+				"a": cty.String,
+				"b": cty.Number,
+			}),
+			Out:       cty.Map(cty.Number),
+			WantClass: AssignUnsafe,
+			WantPaths: []string{`["a"]`},
+		},
+		{
+			In:        cty.Tuple([]cty.Type{cty.String, cty.Number, cty.String}),
+			Out:       cty.List(cty.Number),
+			WantClass: AssignUnsafe,
+			WantPaths: []string{"[0]", "[2]"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v to %#v", test.In, test.Out), func(t *testing.T) {
+			var got Assignability
+			if test.Opts != nil {
+				got = CheckAssignabilityWithOptions(test.In, test.Out, *test.Opts)
+			} else {
+				got = CheckAssignability(test.In, test.Out)
+			}
+
+			if got.Class != test.WantClass {
+				t.Errorf("wrong class\ngot:  %s\nwant: %s", got.Class, test.WantClass)
+			}
+			var gotPaths []string
+			for _, path := range got.UnsafePaths {
+				gotPaths = append(gotPaths, cty.PathPatternFromTypePath(path).String())
+			}
+			if !reflect.DeepEqual(gotPaths, test.WantPaths) {
+				t.Errorf("wrong unsafe paths\ngot:  %q\nwant: %q", gotPaths, test.WantPaths)
+			}
+		})
+	}
+}
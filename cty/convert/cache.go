@@ -0,0 +1,241 @@
+package convert
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Cache remembers the conversions it has found between pairs of types, so
+// that an application that repeatedly converts values between the same few
+// types can avoid the cost of constructing a new Conversion each time.
+//
+// The cache holds at most a fixed number of conversions, discarding the least
+// recently used conversion when it is full. It is safe to use a Cache
+// concurrently from multiple goroutines, and the conversions it returns are
+// safe to call concurrently too.
+//
+// Create a Cache using NewCache or NewCacheWithOptions.
+type Cache struct {
+	c    *converter
+	size int
+
+	mu sync.Mutex
+	// buckets indexes the elements of lru by the hash of their key. Each
+	// bucket is usually only a single element, but types can collide.
+	buckets map[uint64][]*list.Element
+	// lru has a *cacheEntry for each conversion in the cache, with the most
+	// recently used at the front.
+	lru list.List
+}
+
+type cacheKey struct {
+	in, out cty.Type
+	unsafe  bool
+}
+
+type cacheEntry struct {
+	key  cacheKey
+	hash uint64
+	conv conversion
+
+	// identical records whether the input type is identical to the output
+	// type without its optional attributes, in which case Convert can
+	// return values unchanged.
+	identical bool
+}
+
+// NewCache returns a Cache that holds at most the given number of
+// conversions, and that returns the same conversions as GetConversion,
+// GetConversionUnsafe, and Convert.
+//
+// NewCache panics if size is less than one.
+func NewCache(size int) *Cache {
+	return newCache(defaultConverter, size)
+}
+
+// NewCacheWithOptions is like NewCache except that the cache returns the
+// same conversions as GetConversionWithOptions,
+// GetConversionUnsafeWithOptions, and ConvertWithOptions given the same
+// options.
+func NewCacheWithOptions(size int, opts Options) *Cache {
+	return newCache(&converter{opts: opts}, size)
+}
+
+func newCache(c *converter, size int) *Cache {
+	if size < 1 {
+		panic("convert.NewCache requires a size of at least one")
+	}
+	return &Cache{
+		c:       c,
+		size:    size,
+		buckets: make(map[uint64][]*list.Element),
+	}
+}
+
+// GetConversion is like the package-level function of the same name, except
+// that it returns a previously-constructed conversion if one is available in
+// the cache.
+func (cache *Cache) GetConversion(in cty.Type, out cty.Type) Conversion {
+	return retConversion(cache.getConversion(in, out, false).conv)
+}
+
+// GetConversionUnsafe is like the package-level function of the same name,
+// except that it returns a previously-constructed conversion if one is
+// available in the cache.
+func (cache *Cache) GetConversionUnsafe(in cty.Type, out cty.Type) Conversion {
+	return retConversion(cache.getConversion(in, out, true).conv)
+}
+
+// Convert is like the package-level function of the same name, except that
+// it uses a previously-constructed conversion if one is available in the
+// cache.
+func (cache *Cache) Convert(in cty.Value, want cty.Type) (cty.Value, error) {
+	entry := cache.getConversion(in.Type(), want, true)
+	if entry.identical {
+		return in, nil
+	}
+	if entry.conv == nil {
+		return cty.NilVal, cache.c.mismatchError(in.Type(), want)
+	}
+	return entry.conv(in, nil)
+}
+
+// Len returns the number of conversions currently held in the cache,
+// including any results recording that there is no conversion between a pair
+// of types.
+func (cache *Cache) Len() int {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	return cache.lru.Len()
+}
+
+func (cache *Cache) getConversion(in, out cty.Type, unsafe bool) *cacheEntry {
+	key := cacheKey{in, out, unsafe}
+	hash := cacheKeyHash(key)
+
+	cache.mu.Lock()
+	if elem := cache.lookup(key, hash); elem != nil {
+		cache.lru.MoveToFront(elem)
+		entry := elem.Value.(*cacheEntry)
+		cache.mu.Unlock()
+		return entry
+	}
+	cache.mu.Unlock()
+
+	// We construct the conversion without holding the lock so that other
+	// goroutines can use the cache in the meantime. If another goroutine
+	// constructs the same conversion concurrently then we'll keep whichever
+	// is added first, and so both goroutines get the same result.
+	entry := &cacheEntry{
+		key:       key,
+		hash:      hash,
+		conv:      cache.c.getConversion(in, out, unsafe),
+		identical: in.Equals(out.WithoutOptionalAttributesDeep()),
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if elem := cache.lookup(key, hash); elem != nil {
+		cache.lru.MoveToFront(elem)
+		return elem.Value.(*cacheEntry)
+	}
+	cache.buckets[hash] = append(cache.buckets[hash], cache.lru.PushFront(entry))
+	for cache.lru.Len() > cache.size {
+		cache.remove(cache.lru.Back())
+	}
+	return entry
+}
+
+// lookup returns the element of the cache's list for the given key, or nil
+// if there isn't one. The caller must hold the cache's lock.
+func (cache *Cache) lookup(key cacheKey, hash uint64) *list.Element {
+	for _, elem := range cache.buckets[hash] {
+		if elem.Value.(*cacheEntry).key.equals(key) {
+			return elem
+		}
+	}
+	return nil
+}
+
+// remove removes the given element from the cache. The caller must hold the
+// cache's lock.
+func (cache *Cache) remove(elem *list.Element) {
+	entry := cache.lru.Remove(elem).(*cacheEntry)
+	bucket := cache.buckets[entry.hash]
+	for i, candidate := range bucket {
+		if candidate == elem {
+			bucket = append(bucket[:i], bucket[i+1:]...)
+			break
+		}
+	}
+	if len(bucket) == 0 {
+		delete(cache.buckets, entry.hash)
+	} else {
+		cache.buckets[entry.hash] = bucket
+	}
+}
+
+func (k cacheKey) equals(other cacheKey) bool {
+	return k.unsafe == other.unsafe && k.in.Equals(other.in) && k.out.Equals(other.out)
+}
+
+var cacheSeed = maphash.MakeSeed()
+
+// cacheKeyHash returns a hash of the given key that is equal for any two
+// keys whose types are equal, for indexing the cache.
+func cacheKeyHash(k cacheKey) uint64 {
+	h := typeHash(k.in)*31 + typeHash(k.out)
+	if k.unsafe {
+		h++
+	}
+	return h
+}
+
+// typeHash returns a hash of the given type that is equal for any two types
+// that are equal. It avoids allocating, so that looking up a conversion in
+// the cache is much cheaper than constructing it.
+func typeHash(ty cty.Type) uint64 {
+	const (
+		listTag uint64 = iota + 1
+		setTag
+		mapTag
+		tupleTag
+		objectTag
+		optionalTag
+	)
+	switch {
+	case ty.IsListType():
+		return listTag*31 + typeHash(ty.ElementType())
+	case ty.IsSetType():
+		return setTag*31 + typeHash(ty.ElementType())
+	case ty.IsMapType():
+		return mapTag*31 + typeHash(ty.ElementType())
+	case ty.IsTupleType():
+		h := tupleTag
+		for _, ety := range ty.TupleElementTypes() {
+			h = h*31 + typeHash(ety)
+		}
+		return h
+	case ty.IsObjectType():
+		// Attributes are not ordered, so we combine their hashes using an
+		// operation that doesn't depend on the order of iteration.
+		h := objectTag
+		optional := ty.OptionalAttributes()
+		for name, aty := range ty.AttributeTypes() {
+			ah := maphash.String(cacheSeed, name)*31 + typeHash(aty)
+			if _, ok := optional[name]; ok {
+				ah = ah*31 + optionalTag
+			}
+			h += ah
+		}
+		return h
+	default:
+		// The primitive types and cty.DynamicPseudoType each have a unique
+		// friendly name. Capsule types are equal only if they are the same
+		// type, but distinct capsule types rarely share a name.
+		return maphash.String(cacheSeed, ty.FriendlyName())
+	}
+}
@@ -0,0 +1,259 @@
+package convert
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache(2)
+
+	objTy := cty.Object(map[string]cty.Type{
+		"a": cty.Number,
+		"b": cty.List(cty.Bool),
+	})
+	optTy := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"a": cty.Number,
+		"b": cty.List(cty.Bool),
+	}, []string{"b"})
+	val := cty.ObjectVal(map[string]cty.Value{
+		"a": cty.StringVal("1"),
+		"b": cty.TupleVal([]cty.Value{cty.StringVal("true")}),
+	})
+
+	if conv := cache.GetConversion(val.Type(), objTy); conv != nil {
+		t.Fatalf("unexpected safe conversion")
+	}
+	if conv := cache.GetConversionUnsafe(val.Type(), objTy); conv == nil {
+		t.Fatalf("no unsafe conversion")
+	}
+	if got, want := cache.Len(), 2; got != want {
+		t.Fatalf("wrong number of entries %d; want %d", got, want)
+	}
+
+	for range 2 {
+		got, err := cache.Convert(val, objTy)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		want := cty.ObjectVal(map[string]cty.Value{
+			"a": cty.NumberIntVal(1),
+			"b": cty.ListVal([]cty.Value{cty.True}),
+		})
+		if !got.RawEquals(want) {
+			t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+		}
+	}
+	if got, want := cache.Len(), 2; got != want {
+		t.Fatalf("wrong number of entries %d after reusing a conversion; want %d", got, want)
+	}
+
+	// The optional attribute makes this a different type, which must not
+	// share the conversion for objTy. Adding it evicts the least recently
+	// used conversion, which is the safe conversion we requested first.
+	if _, err := cache.Convert(val, optTy); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := cache.Len(), 2; got != want {
+		t.Fatalf("wrong number of entries %d after eviction; want %d", got, want)
+	}
+	if elem := cache.lookup(cacheKey{val.Type(), objTy, false}, cacheKeyHash(cacheKey{val.Type(), objTy, false})); elem != nil {
+		t.Fatalf("least recently used conversion was not evicted")
+	}
+	if elem := cache.lookup(cacheKey{val.Type(), objTy, true}, cacheKeyHash(cacheKey{val.Type(), objTy, true})); elem == nil {
+		t.Fatalf("recently used conversion was evicted")
+	}
+
+	_, err := cache.Convert(cty.True, cty.Number)
+	if err == nil {
+		t.Fatalf("no error for impossible conversion")
+	}
+	if got, want := err.Error(), "number required, but have bool"; got != want {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestCacheWithOptions(t *testing.T) {
+	cache := NewCacheWithOptions(10, Strict)
+	_, err := cache.Convert(cty.StringVal("1"), cty.Number)
+	if err == nil {
+		t.Fatalf("no error for disabled conversion")
+	}
+	if conv := cache.GetConversion(cty.Number, cty.String); conv != nil {
+		t.Fatalf("unexpected conversion for disabled conversion")
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	cache := NewCache(4)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				ty := cty.Object(map[string]cty.Type{
+					"a": cty.Number,
+					"b": cty.List(cty.Number),
+				})
+				val := cty.ObjectVal(map[string]cty.Value{
+					"a": cty.StringVal(fmt.Sprint(j)),
+					"b": cty.TupleVal([]cty.Value{cty.StringVal("1"), cty.UnknownVal(cty.String)}),
+				})
+				if (i+j)%3 == 0 {
+					ty = cty.Map(cty.String)
+					val = cty.ObjectVal(map[string]cty.Value{
+						"a": cty.NumberIntVal(int64(j)),
+					})
+				}
+				if _, err := cache.Convert(val, ty); err != nil {
+					t.Errorf("unexpected error: %s", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTypeHash(t *testing.T) {
+	// Types that are equal must always have the same hash, regardless of
+	// how they were constructed.
+	a := cty.Object(map[string]cty.Type{
+		"a": cty.String,
+		"b": cty.List(cty.Number),
+		"c": cty.Tuple([]cty.Type{cty.Bool, cty.DynamicPseudoType}),
+	})
+	b := cty.Object(map[string]cty.Type{
+		"c": cty.Tuple([]cty.Type{cty.Bool, cty.DynamicPseudoType}),
+		"b": cty.List(cty.Number),
+		"a": cty.String,
+	})
+	if typeHash(a) != typeHash(b) {
+		t.Errorf("equal types have different hashes")
+	}
+
+	// Different types usually have different hashes, although that isn't
+	// guaranteed in general.
+	types := []cty.Type{
+		cty.String,
+		cty.Number,
+		cty.Bool,
+		cty.DynamicPseudoType,
+		cty.List(cty.String),
+		cty.Set(cty.String),
+		cty.Map(cty.String),
+		cty.Tuple([]cty.Type{cty.String}),
+		cty.Tuple([]cty.Type{cty.String, cty.String}),
+		a,
+		cty.ObjectWithOptionalAttrs(a.AttributeTypes(), []string{"a"}),
+		cty.ObjectWithOptionalAttrs(a.AttributeTypes(), []string{"b"}),
+	}
+	seen := make(map[uint64]cty.Type)
+	for _, ty := range types {
+		h := typeHash(ty)
+		if other, exists := seen[h]; exists {
+			t.Errorf("%#v has the same hash as %#v", ty, other)
+		}
+		seen[h] = ty
+	}
+}
+
+// deepObjectType returns an object type with the given number of attributes
+// at each level, nested to the given depth, with a list of objects at each
+// level.
+func deepObjectType(depth, width int, leaf cty.Type) cty.Type {
+	if depth == 0 {
+		return leaf
+	}
+	atys := make(map[string]cty.Type, width+1)
+	for i := range width {
+		atys[fmt.Sprintf("attr%d", i)] = leaf
+	}
+	atys["nested"] = cty.List(deepObjectType(depth-1, width, leaf))
+	return cty.Object(atys)
+}
+
+// deepObjectVal returns a value of a type returned by deepObjectType, with a
+// single element in each of the nested lists.
+func deepObjectVal(depth, width int, leaf cty.Value) cty.Value {
+	if depth == 0 {
+		return leaf
+	}
+	vals := make(map[string]cty.Value, width+1)
+	for i := range width {
+		vals[fmt.Sprintf("attr%d", i)] = leaf
+	}
+	vals["nested"] = cty.ListVal([]cty.Value{deepObjectVal(depth-1, width, leaf)})
+	return cty.ObjectVal(vals)
+}
+
+// deepOptionalObjectType is like deepObjectType except that each object type
+// also has an optional attribute named "extra".
+func deepOptionalObjectType(depth, width int, leaf cty.Type) cty.Type {
+	if depth == 0 {
+		return leaf
+	}
+	atys := make(map[string]cty.Type, width+2)
+	for i := range width {
+		atys[fmt.Sprintf("attr%d", i)] = leaf
+	}
+	atys["extra"] = leaf
+	atys["nested"] = cty.List(deepOptionalObjectType(depth-1, width, leaf))
+	return cty.ObjectWithOptionalAttrs(atys, []string{"extra"})
+}
+
+func BenchmarkGetConversionDeepObject(b *testing.B) {
+	in := deepObjectType(5, 8, cty.Number)
+	out := deepObjectType(5, 8, cty.String)
+
+	b.Run("uncached", func(b *testing.B) {
+		for b.Loop() {
+			GetConversion(in, out)
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		cache := NewCache(16)
+		for b.Loop() {
+			cache.GetConversion(in, out)
+		}
+	})
+}
+
+func BenchmarkConvertDeepObject(b *testing.B) {
+	b.Run("optional attributes", func(b *testing.B) {
+		// The values already have the wanted attribute types, so most of the
+		// cost of an uncached conversion is constructing it.
+		val := deepObjectVal(5, 8, cty.StringVal("a"))
+		want := deepOptionalObjectType(5, 8, cty.String)
+		benchmarkConvert(b, val, want)
+	})
+	b.Run("numbers to strings", func(b *testing.B) {
+		// Formatting each number as a string dominates the cost of these
+		// conversions, so the cache makes less difference.
+		val := deepObjectVal(5, 8, cty.NumberIntVal(1))
+		want := deepObjectType(5, 8, cty.String)
+		benchmarkConvert(b, val, want)
+	})
+}
+
+func benchmarkConvert(b *testing.B, val cty.Value, want cty.Type) {
+	b.Run("uncached", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := Convert(val, want); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("cached", func(b *testing.B) {
+		cache := NewCache(16)
+		for b.Loop() {
+			if _, err := cache.Convert(val, want); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -4,12 +4,34 @@ import (
 	"github.com/zclconf/go-cty/cty"
 )
 
+// converter holds the settings that customize the behavior of the conversion,
+// unification, and mismatch message functions in this package. The exported
+// functions that don't take any options use defaultConverter.
+type converter struct {
+	opts  Options
+	rules []Rule
+}
+
+var defaultConverter = &converter{}
+
+func (c *converter) convert(in cty.Value, want cty.Type) (cty.Value, error) {
+	if in.Type().Equals(want.WithoutOptionalAttributesDeep()) {
+		return in, nil
+	}
+
+	conv := c.getConversion(in.Type(), want, true)
+	if conv == nil {
+		return cty.NilVal, c.mismatchError(in.Type(), want)
+	}
+	return conv(in, nil)
+}
+
 // conversion is an internal variant of Conversion that carries around
 // a cty.Path to be used in error responses.
 type conversion func(cty.Value, cty.Path) (cty.Value, error)
 
-func getConversion(in cty.Type, out cty.Type, unsafe bool) conversion {
-	conv := getConversionKnown(in, out, unsafe)
+func (c *converter) getConversion(in cty.Type, out cty.Type, unsafe bool) conversion {
+	conv := c.getConversionKnown(in, out, unsafe)
 	if conv == nil {
 		return nil
 	}
@@ -40,17 +62,17 @@ func getConversion(in cty.Type, out cty.Type, unsafe bool) conversion {
 			// attributes from the type. Unknown and null pass through values
 			// must do the same to ensure that homogeneous collections have a
 			// single element type.
-			out = out.WithoutOptionalAttributesDeep()
+			out := out.WithoutOptionalAttributesDeep()
 
 			if !isKnown {
-				return prepareUnknownResult(in.Range(), dynamicReplace(in.Type(), out)), nil
+				return prepareUnknownResult(in.Range(), c.dynamicReplace(in.Type(), out)), nil
 			}
 
 			if isNull {
 				// We'll pass through nulls, albeit type converted, and let
 				// the caller deal with whatever handling they want to do in
 				// case null values are considered valid in some applications.
-				return cty.NullVal(dynamicReplace(in.Type(), out)), nil
+				return cty.NullVal(c.dynamicReplace(in.Type(), out)), nil
 			}
 		}
 
@@ -60,7 +82,15 @@ func getConversion(in cty.Type, out cty.Type, unsafe bool) conversion {
 	return ret
 }
 
-func getConversionKnown(in cty.Type, out cty.Type, unsafe bool) conversion {
+func (c *converter) getConversionKnown(in cty.Type, out cty.Type, unsafe bool) conversion {
+	if in != cty.DynamicPseudoType && out != cty.DynamicPseudoType {
+		// Custom rules take priority over all of the built-in rules except
+		// those for cty.DynamicPseudoType.
+		if conv := c.ruleConversion(in, out, unsafe); conv != nil {
+			return conv
+		}
+	}
+
 	switch {
 
 	case out == cty.DynamicPseudoType:
@@ -74,9 +104,12 @@ func getConversionKnown(in cty.Type, out cty.Type, unsafe bool) conversion {
 		// whose type isn't yet known during type checking. For these we will
 		// assume that conversion will succeed and deal with any errors that
 		// result (which is why we can only do this when "unsafe" is set).
-		return dynamicFixup(out)
+		return c.dynamicFixup(out)
 
 	case in.IsPrimitiveType() && out.IsPrimitiveType():
+		if c.primitiveConversionDisabled(in, out) {
+			return nil
+		}
 		conv := primitiveConversionsSafe[in][out]
 		if conv != nil {
 			return conv
@@ -87,10 +120,10 @@ func getConversionKnown(in cty.Type, out cty.Type, unsafe bool) conversion {
 		return nil
 
 	case out.IsObjectType() && in.IsObjectType():
-		return conversionObjectToObject(in, out, unsafe)
+		return c.conversionObjectToObject(in, out, unsafe)
 
 	case out.IsTupleType() && in.IsTupleType():
-		return conversionTupleToTuple(in, out, unsafe)
+		return c.conversionTupleToTuple(in, out, unsafe)
 
 	case out.IsListType() && (in.IsListType() || in.IsSetType()):
 		inEty := in.ElementType()
@@ -101,7 +134,7 @@ func getConversionKnown(in cty.Type, out cty.Type, unsafe bool) conversion {
 			return conversionCollectionToList(outEty, nil)
 		}
 
-		convEty := getConversion(inEty, outEty, unsafe)
+		convEty := c.getConversion(inEty, outEty, unsafe)
 		if convEty == nil {
 			return nil
 		}
@@ -116,7 +149,7 @@ func getConversionKnown(in cty.Type, out cty.Type, unsafe bool) conversion {
 		}
 		inEty := in.ElementType()
 		outEty := out.ElementType()
-		convEty := getConversion(inEty, outEty, unsafe)
+		convEty := c.getConversion(inEty, outEty, unsafe)
 		if inEty.Equals(outEty) {
 			// This indicates that we're converting from set to list with
 			// the same element type, so we don't need an element converter.
@@ -131,23 +164,32 @@ func getConversionKnown(in cty.Type, out cty.Type, unsafe bool) conversion {
 	case out.IsMapType() && in.IsMapType():
 		inEty := in.ElementType()
 		outEty := out.ElementType()
-		convEty := getConversion(inEty, outEty, unsafe)
+		convEty := c.getConversion(inEty, outEty, unsafe)
 		if convEty == nil {
 			return nil
 		}
-		return conversionCollectionToMap(outEty, convEty)
+		return c.conversionCollectionToMap(outEty, convEty)
 
 	case out.IsListType() && in.IsTupleType():
+		if c.opts.DisableTupleToList {
+			return nil
+		}
 		outEty := out.ElementType()
-		return conversionTupleToList(in, outEty, unsafe)
+		return c.conversionTupleToList(in, outEty, unsafe)
 
 	case out.IsSetType() && in.IsTupleType():
+		if c.opts.DisableTupleToList {
+			return nil
+		}
 		outEty := out.ElementType()
-		return conversionTupleToSet(in, outEty, unsafe)
+		return c.conversionTupleToSet(in, outEty, unsafe)
 
 	case out.IsMapType() && in.IsObjectType():
+		if c.opts.DisableObjectToMap {
+			return nil
+		}
 		outEty := out.ElementType()
-		return conversionObjectToMap(in, outEty, unsafe)
+		return c.conversionObjectToMap(in, outEty, unsafe)
 
 	case out.IsObjectType() && in.IsMapType():
 		if !unsafe {
@@ -156,7 +198,7 @@ func getConversionKnown(in cty.Type, out cty.Type, unsafe bool) conversion {
 			// object attributes.
 			return nil
 		}
-		return conversionMapToObject(in, out, unsafe)
+		return c.conversionMapToObject(in, out, unsafe)
 
 	case in.IsCapsuleType() || out.IsCapsuleType():
 		if !unsafe {
@@ -1,6 +1,9 @@
 package convert
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -59,7 +62,7 @@ func conversionCollectionToList(ety cty.Type, conv conversion) conversion {
 		}
 
 		if !cty.CanListVal(elems) {
-			return cty.NilVal, path.NewErrorf("element types must all match for conversion to list")
+			return cty.NilVal, path.NewError(elementTypeError(val.Type(), cty.List(ety), errors.New("element types must all match for conversion to list")))
 		}
 
 		return cty.ListVal(elems), nil
@@ -113,7 +116,7 @@ func conversionCollectionToSet(ety cty.Type, conv conversion) conversion {
 		}
 
 		if !cty.CanSetVal(elems) {
-			return cty.NilVal, path.NewErrorf("element types must all match for conversion to set")
+			return cty.NilVal, path.NewError(elementTypeError(val.Type(), cty.Set(ety), errors.New("element types must all match for conversion to set")))
 		}
 
 		return cty.SetVal(elems), nil
@@ -126,10 +129,11 @@ func conversionCollectionToSet(ety cty.Type, conv conversion) conversion {
 //
 // "conv" can be nil if the elements are expected to already be of the
 // correct type and just need to be re-wrapped into a map.
-func conversionCollectionToMap(ety cty.Type, conv conversion) conversion {
+func (c *converter) conversionCollectionToMap(ety cty.Type, conv conversion) conversion {
 	return func(val cty.Value, path cty.Path) (cty.Value, error) {
 		elems := make(map[string]cty.Value, 0)
 		elemPath := append(path.Copy(), nil)
+		collTy := val.Type()
 		it := val.ElementIterator()
 		for it.Next() {
 			key, val := it.Element()
@@ -143,7 +147,7 @@ func conversionCollectionToMap(ety cty.Type, conv conversion) conversion {
 			if err != nil {
 				// Should never happen, because keys can only be numbers or
 				// strings and both can convert to string.
-				return cty.DynamicVal, elemPath.NewErrorf("cannot convert key type %s to string for map", key.Type().FriendlyName())
+				return cty.DynamicVal, elemPath.NewError(elementTypeError(collTy, cty.Map(ety), fmt.Errorf("cannot convert key type %s to string for map", key.Type().FriendlyName())))
 			}
 
 			if conv != nil {
@@ -167,13 +171,13 @@ func conversionCollectionToMap(ety cty.Type, conv conversion) conversion {
 
 		if ety.IsCollectionType() || ety.IsObjectType() {
 			var err error
-			if elems, err = conversionUnifyCollectionElements(elems, path, false); err != nil {
+			if elems, err = c.conversionUnifyCollectionElements(val.Type(), cty.Map(ety), elems, path, false); err != nil {
 				return cty.NilVal, err
 			}
 		}
 
 		if !cty.CanMapVal(elems) {
-			return cty.NilVal, path.NewErrorf("element types must all match for conversion to map")
+			return cty.NilVal, path.NewError(elementTypeError(val.Type(), cty.Map(ety), errors.New("element types must all match for conversion to map")))
 		}
 
 		return cty.MapVal(elems), nil
@@ -184,7 +188,7 @@ func conversionCollectionToMap(ety cty.Type, conv conversion) conversion {
 // given tuple type and return a set of the given element type.
 //
 // Will panic if the given tupleType isn't actually a tuple type.
-func conversionTupleToSet(tupleType cty.Type, setEty cty.Type, unsafe bool) conversion {
+func (c *converter) conversionTupleToSet(tupleType cty.Type, setEty cty.Type, unsafe bool) conversion {
 	tupleEtys := tupleType.TupleElementTypes()
 
 	if len(tupleEtys) == 0 {
@@ -198,7 +202,7 @@ func conversionTupleToSet(tupleType cty.Type, setEty cty.Type, unsafe bool) conv
 		// This is a special case where the caller wants us to find
 		// a suitable single type that all elements can convert to, if
 		// possible.
-		setEty, _ = unify(tupleEtys, unsafe)
+		setEty, _ = c.unify(tupleEtys, unsafe)
 		if setEty == cty.NilType {
 			return nil
 		}
@@ -222,7 +226,7 @@ func conversionTupleToSet(tupleType cty.Type, setEty cty.Type, unsafe bool) conv
 			continue
 		}
 
-		elemConvs[i] = getConversion(tupleEty, setEty, unsafe)
+		elemConvs[i] = c.getConversion(tupleEty, setEty, unsafe)
 		if elemConvs[i] == nil {
 			// If any of our element conversions are impossible, then the our
 			// whole conversion is impossible.
@@ -263,7 +267,7 @@ func conversionTupleToSet(tupleType cty.Type, setEty cty.Type, unsafe bool) conv
 		}
 
 		if !cty.CanSetVal(elems) {
-			return cty.NilVal, path.NewErrorf("element types must all match for conversion to set")
+			return cty.NilVal, path.NewError(elementTypeError(val.Type(), cty.Set(setEty), errors.New("element types must all match for conversion to set")))
 		}
 
 		return cty.SetVal(elems), nil
@@ -274,7 +278,7 @@ func conversionTupleToSet(tupleType cty.Type, setEty cty.Type, unsafe bool) conv
 // given tuple type and return a list of the given element type.
 //
 // Will panic if the given tupleType isn't actually a tuple type.
-func conversionTupleToList(tupleType cty.Type, listEty cty.Type, unsafe bool) conversion {
+func (c *converter) conversionTupleToList(tupleType cty.Type, listEty cty.Type, unsafe bool) conversion {
 	tupleEtys := tupleType.TupleElementTypes()
 
 	if len(tupleEtys) == 0 {
@@ -288,7 +292,7 @@ func conversionTupleToList(tupleType cty.Type, listEty cty.Type, unsafe bool) co
 		// This is a special case where the caller wants us to find
 		// a suitable single type that all elements can convert to, if
 		// possible.
-		listEty, _ = unify(tupleEtys, unsafe)
+		listEty, _ = c.unify(tupleEtys, unsafe)
 		if listEty == cty.NilType {
 			return nil
 		}
@@ -312,7 +316,7 @@ func conversionTupleToList(tupleType cty.Type, listEty cty.Type, unsafe bool) co
 			continue
 		}
 
-		elemConvs[i] = getConversion(tupleEty, listEty, unsafe)
+		elemConvs[i] = c.getConversion(tupleEty, listEty, unsafe)
 		if elemConvs[i] == nil {
 			// If any of our element conversions are impossible, then the our
 			// whole conversion is impossible.
@@ -349,13 +353,13 @@ func conversionTupleToList(tupleType cty.Type, listEty cty.Type, unsafe bool) co
 			i++
 		}
 
-		elems, err := conversionUnifyListElements(elems, elemPath, unsafe)
+		elems, err := c.conversionUnifyListElements(val.Type(), cty.List(listEty), elems, elemPath, unsafe)
 		if err != nil {
 			return cty.NilVal, err
 		}
 
 		if !cty.CanListVal(elems) {
-			return cty.NilVal, path.NewErrorf("element types must all match for conversion to list")
+			return cty.NilVal, path.NewError(elementTypeError(val.Type(), cty.List(listEty), errors.New("element types must all match for conversion to list")))
 		}
 
 		return cty.ListVal(elems), nil
@@ -366,7 +370,7 @@ func conversionTupleToList(tupleType cty.Type, listEty cty.Type, unsafe bool) co
 // given object type and return a map of the given element type.
 //
 // Will panic if the given objectType isn't actually an object type.
-func conversionObjectToMap(objectType cty.Type, mapEty cty.Type, unsafe bool) conversion {
+func (c *converter) conversionObjectToMap(objectType cty.Type, mapEty cty.Type, unsafe bool) conversion {
 	objectAtys := objectType.AttributeTypes()
 
 	if len(objectAtys) == 0 {
@@ -384,7 +388,7 @@ func conversionObjectToMap(objectType cty.Type, mapEty cty.Type, unsafe bool) co
 		for _, aty := range objectAtys {
 			objectAtysList = append(objectAtysList, aty)
 		}
-		mapEty, _ = unify(objectAtysList, unsafe)
+		mapEty, _ = c.unify(objectAtysList, unsafe)
 		if mapEty == cty.NilType {
 			return nil
 		}
@@ -397,7 +401,7 @@ func conversionObjectToMap(objectType cty.Type, mapEty cty.Type, unsafe bool) co
 			continue
 		}
 
-		elemConvs[name] = getConversion(objectAty, mapEty, unsafe)
+		elemConvs[name] = c.getConversion(objectAty, mapEty, unsafe)
 		if elemConvs[name] == nil {
 			// If any of our element conversions are impossible, then the our
 			// whole conversion is impossible.
@@ -431,13 +435,13 @@ func conversionObjectToMap(objectType cty.Type, mapEty cty.Type, unsafe bool) co
 
 		if mapEty.IsCollectionType() || mapEty.IsObjectType() {
 			var err error
-			if elems, err = conversionUnifyCollectionElements(elems, path, unsafe); err != nil {
+			if elems, err = c.conversionUnifyCollectionElements(val.Type(), cty.Map(mapEty), elems, path, unsafe); err != nil {
 				return cty.NilVal, err
 			}
 		}
 
 		if !cty.CanMapVal(elems) {
-			return cty.NilVal, path.NewErrorf("attribute types must all match for conversion to map")
+			return cty.NilVal, path.NewError(elementTypeError(val.Type(), cty.Map(mapEty), errors.New("attribute types must all match for conversion to map")))
 		}
 
 		return cty.MapVal(elems), nil
@@ -450,7 +454,7 @@ func conversionObjectToMap(objectType cty.Type, mapEty cty.Type, unsafe bool) co
 //
 // Will panic if the given mapType and objType are not maps and objects
 // respectively.
-func conversionMapToObject(mapType cty.Type, objType cty.Type, unsafe bool) conversion {
+func (c *converter) conversionMapToObject(mapType cty.Type, objType cty.Type, unsafe bool) conversion {
 	objectAtys := objType.AttributeTypes()
 	mapEty := mapType.ElementType()
 
@@ -461,7 +465,7 @@ func conversionMapToObject(mapType cty.Type, objType cty.Type, unsafe bool) conv
 			continue
 		}
 
-		elemConvs[name] = getConversion(mapEty, objectAty, unsafe)
+		elemConvs[name] = c.getConversion(mapEty, objectAty, unsafe)
 		if elemConvs[name] == nil {
 			// This means that this conversion is impossible. Typically, we
 			// would give up at this point and declare the whole conversion
@@ -536,7 +540,7 @@ func conversionMapToObject(mapType cty.Type, objType cty.Type, unsafe bool) conv
 				// Since we reached this branch, we know that map did actually
 				// contain a non-convertable optional attribute. This means we
 				// error.
-				return cty.NilVal, path.NewErrorf("map element type is incompatible with attribute %q: %s", name.AsString(), MismatchMessage(val.Type(), objType.AttributeType(name.AsString())))
+				return cty.NilVal, path.NewError(fmt.Errorf("map element type is incompatible with attribute %q: %w", name.AsString(), c.mismatchError(val.Type(), objType.AttributeType(name.AsString()))))
 			}
 
 			if val.IsNull() {
@@ -551,7 +555,7 @@ func conversionMapToObject(mapType cty.Type, objType cty.Type, unsafe bool) conv
 				if optional := objType.AttributeOptional(name); optional {
 					elems[name] = cty.NullVal(aty)
 				} else {
-					return cty.NilVal, path.NewErrorf("map has no element for required attribute %q", name)
+					return cty.NilVal, path.NewError(missingMapElementError(mapType, objType, name))
 				}
 			}
 		}
@@ -560,14 +564,14 @@ func conversionMapToObject(mapType cty.Type, objType cty.Type, unsafe bool) conv
 	}
 }
 
-func conversionUnifyCollectionElements(elems map[string]cty.Value, path cty.Path, unsafe bool) (map[string]cty.Value, error) {
+func (c *converter) conversionUnifyCollectionElements(got, want cty.Type, elems map[string]cty.Value, path cty.Path, unsafe bool) (map[string]cty.Value, error) {
 	elemTypes := make([]cty.Type, 0, len(elems))
 	for _, elem := range elems {
 		elemTypes = append(elemTypes, elem.Type())
 	}
-	unifiedType, _ := unify(elemTypes, unsafe)
+	unifiedType, _ := c.unify(elemTypes, unsafe)
 	if unifiedType == cty.NilType {
-		return nil, path.NewErrorf("cannot find a common base type for all elements")
+		return nil, path.NewError(elementTypeError(got, want, errors.New("cannot find a common base type for all elements")))
 	}
 
 	unifiedElems := make(map[string]cty.Value)
@@ -578,7 +582,7 @@ func conversionUnifyCollectionElements(elems map[string]cty.Value, path cty.Path
 			unifiedElems[name] = elem
 			continue
 		}
-		conv := getConversion(elem.Type(), unifiedType, unsafe)
+		conv := c.getConversion(elem.Type(), unifiedType, unsafe)
 		if conv == nil {
 		}
 		elemPath[len(elemPath)-1] = cty.IndexStep{
@@ -594,14 +598,14 @@ func conversionUnifyCollectionElements(elems map[string]cty.Value, path cty.Path
 	return unifiedElems, nil
 }
 
-func conversionUnifyListElements(elems []cty.Value, path cty.Path, unsafe bool) ([]cty.Value, error) {
+func (c *converter) conversionUnifyListElements(got, want cty.Type, elems []cty.Value, path cty.Path, unsafe bool) ([]cty.Value, error) {
 	elemTypes := make([]cty.Type, len(elems))
 	for i, elem := range elems {
 		elemTypes[i] = elem.Type()
 	}
-	unifiedType, _ := unify(elemTypes, unsafe)
+	unifiedType, _ := c.unify(elemTypes, unsafe)
 	if unifiedType == cty.NilType {
-		return nil, path.NewErrorf("cannot find a common base type for all elements")
+		return nil, path.NewError(elementTypeError(got, want, errors.New("cannot find a common base type for all elements")))
 	}
 
 	ret := make([]cty.Value, len(elems))
@@ -612,7 +616,7 @@ func conversionUnifyListElements(elems []cty.Value, path cty.Path, unsafe bool)
 			ret[i] = elem
 			continue
 		}
-		conv := getConversion(elem.Type(), unifiedType, unsafe)
+		conv := c.getConversion(elem.Type(), unifiedType, unsafe)
 		if conv == nil {
 		}
 		elemPath[len(elemPath)-1] = cty.IndexStep{
@@ -12,9 +12,9 @@ import (
 // This is in the spirit of the cty philosophy of optimistically assuming that
 // DynamicPseudoType values will become the intended value eventually, and
 // dealing with any inconsistencies during final evaluation.
-func dynamicFixup(wantType cty.Type) conversion {
+func (c *converter) dynamicFixup(wantType cty.Type) conversion {
 	return func(in cty.Value, path cty.Path) (cty.Value, error) {
-		ret, err := Convert(in, wantType)
+		ret, err := c.convert(in, wantType)
 		if err != nil {
 			// Re-wrap this error so that the returned path is relative
 			// to the caller's original value, rather than relative to our
@@ -45,7 +45,7 @@ func dynamicPassthrough(in cty.Value, path cty.Path) (cty.Value, error) {
 // objects with optional attributes when the optional attributes don't match
 // the map element type. Therefor in the case of a non-primitive type mismatch,
 // we have to assume conversion was possible and pass the out type through.
-func dynamicReplace(in, out cty.Type) cty.Type {
+func (c *converter) dynamicReplace(in, out cty.Type) cty.Type {
 	if in == cty.DynamicPseudoType || in == cty.NilType {
 		// Short circuit this case, there's no point worrying about this if in
 		// is a dynamic type or a nil type. Out is the best we can do.
@@ -63,7 +63,7 @@ func dynamicReplace(in, out cty.Type) cty.Type {
 	case out.IsMapType():
 		// Maps are compatible with other maps or objects.
 		if in.IsMapType() {
-			return cty.Map(dynamicReplace(in.ElementType(), out.ElementType()))
+			return cty.Map(c.dynamicReplace(in.ElementType(), out.ElementType()))
 		}
 
 		if in.IsObjectType() {
@@ -71,17 +71,22 @@ func dynamicReplace(in, out cty.Type) cty.Type {
 			for _, t := range in.AttributeTypes() {
 				types = append(types, t)
 			}
-			unifiedType, _ := unify(types, true)
-			return cty.Map(dynamicReplace(unifiedType, out.ElementType()))
+			unifiedType, _ := c.unify(types, true)
+			return cty.Map(c.dynamicReplace(unifiedType, out.ElementType()))
 		}
 
 		return out
 	case out.IsObjectType():
 		// Objects are compatible with other objects and maps.
+		if !in.IsMapType() && !in.IsObjectType() {
+			// Only a custom rule can convert other types to objects, and
+			// so there's nothing to replace.
+			return out
+		}
 		outTypes := map[string]cty.Type{}
 		if in.IsMapType() {
 			for attr, attrType := range out.AttributeTypes() {
-				outTypes[attr] = dynamicReplace(in.ElementType(), attrType)
+				outTypes[attr] = c.dynamicReplace(in.ElementType(), attrType)
 			}
 		}
 
@@ -94,7 +99,7 @@ func dynamicReplace(in, out cty.Type) cty.Type {
 					outTypes[attr] = attrType
 					continue
 				}
-				outTypes[attr] = dynamicReplace(in.AttributeType(attr), attrType)
+				outTypes[attr] = c.dynamicReplace(in.AttributeType(attr), attrType)
 			}
 		}
 
@@ -102,32 +107,36 @@ func dynamicReplace(in, out cty.Type) cty.Type {
 	case out.IsSetType():
 		// Sets are compatible with other sets, lists, tuples.
 		if in.IsSetType() || in.IsListType() {
-			return cty.Set(dynamicReplace(in.ElementType(), out.ElementType()))
+			return cty.Set(c.dynamicReplace(in.ElementType(), out.ElementType()))
 		}
 
 		if in.IsTupleType() {
-			unifiedType, _ := unify(in.TupleElementTypes(), true)
-			return cty.Set(dynamicReplace(unifiedType, out.ElementType()))
+			unifiedType, _ := c.unify(in.TupleElementTypes(), true)
+			return cty.Set(c.dynamicReplace(unifiedType, out.ElementType()))
 		}
 
 		return out
 	case out.IsListType():
 		// Lists are compatible with other lists, sets, and tuples.
 		if in.IsSetType() || in.IsListType() {
-			return cty.List(dynamicReplace(in.ElementType(), out.ElementType()))
+			return cty.List(c.dynamicReplace(in.ElementType(), out.ElementType()))
 		}
 
 		if in.IsTupleType() {
-			unifiedType, _ := unify(in.TupleElementTypes(), true)
-			return cty.List(dynamicReplace(unifiedType, out.ElementType()))
+			unifiedType, _ := c.unify(in.TupleElementTypes(), true)
+			return cty.List(c.dynamicReplace(unifiedType, out.ElementType()))
 		}
 
 		return out
 	case out.IsTupleType():
-		// Tuples are only compatible with other tuples
+		// Tuples are only compatible with other tuples, except when a custom
+		// rule converts to them.
+		if !in.IsTupleType() {
+			return out
+		}
 		var types []cty.Type
 		for ix := 0; ix < len(out.TupleElementTypes()); ix++ {
-			types = append(types, dynamicReplace(in.TupleElementType(ix), out.TupleElementType(ix)))
+			types = append(types, c.dynamicReplace(in.TupleElementType(ix), out.TupleElementType(ix)))
 		}
 		return cty.Tuple(types)
 	default:
@@ -18,7 +18,7 @@ import (
 // Shallow object conversions work the same for both safe and unsafe modes,
 // but the safety flag is passed on to recursive conversions and may thus
 // limit the above definition of "subset".
-func conversionObjectToObject(in, out cty.Type, unsafe bool) conversion {
+func (c *converter) conversionObjectToObject(in, out cty.Type, unsafe bool) conversion {
 	inAtys := in.AttributeTypes()
 	outAtys := out.AttributeTypes()
 	outOptionals := out.OptionalAttributes()
@@ -45,7 +45,7 @@ func conversionObjectToObject(in, out cty.Type, unsafe bool) conversion {
 			continue
 		}
 
-		attrConvs[name] = getConversion(inAty, outAty, unsafe)
+		attrConvs[name] = c.getConversion(inAty, outAty, unsafe)
 		if attrConvs[name] == nil {
 			// If a recursive conversion isn't available, then our top-level
 			// configuration is impossible too.
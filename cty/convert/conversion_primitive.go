@@ -32,7 +32,7 @@ var primitiveConversionsUnsafe = map[cty.Type]map[cty.Type]conversion{
 		cty.Number: func(val cty.Value, path cty.Path) (cty.Value, error) {
 			v, err := cty.ParseNumberVal(val.AsString())
 			if err != nil {
-				return cty.NilVal, path.NewErrorf("a number is required")
+				return cty.NilVal, path.NewError(typeMismatchError(cty.String, cty.Number, "a number is required"))
 			}
 			return v, nil
 		},
@@ -45,11 +45,11 @@ var primitiveConversionsUnsafe = map[cty.Type]map[cty.Type]conversion{
 			default:
 				switch strings.ToLower(val.AsString()) {
 				case "true":
-					return cty.NilVal, path.NewErrorf("a bool is required; to convert from string, use lowercase \"true\"")
+					return cty.NilVal, path.NewError(typeMismatchError(cty.String, cty.Bool, `a bool is required; to convert from string, use lowercase "true"`))
 				case "false":
-					return cty.NilVal, path.NewErrorf("a bool is required; to convert from string, use lowercase \"false\"")
+					return cty.NilVal, path.NewError(typeMismatchError(cty.String, cty.Bool, `a bool is required; to convert from string, use lowercase "false"`))
 				default:
-					return cty.NilVal, path.NewErrorf("a bool is required")
+					return cty.NilVal, path.NewError(typeMismatchError(cty.String, cty.Bool, "a bool is required"))
 				}
 			}
 		},
@@ -13,7 +13,7 @@ import (
 // Shallow tuple conversions work the same for both safe and unsafe modes,
 // but the safety flag is passed on to recursive conversions and may thus
 // limit which element type conversions are possible.
-func conversionTupleToTuple(in, out cty.Type, unsafe bool) conversion {
+func (c *converter) conversionTupleToTuple(in, out cty.Type, unsafe bool) conversion {
 	inEtys := in.TupleElementTypes()
 	outEtys := out.TupleElementTypes()
 
@@ -31,7 +31,7 @@ func conversionTupleToTuple(in, out cty.Type, unsafe bool) conversion {
 			continue
 		}
 
-		elemConvs[i] = getConversion(inEty, outEty, unsafe)
+		elemConvs[i] = c.getConversion(inEty, outEty, unsafe)
 		if elemConvs[i] == nil {
 			// If a recursive conversion isn't available, then our top-level
 			// configuration is impossible too.
@@ -0,0 +1,204 @@
+package convert
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// ConvertAll is like Convert except that, rather than stopping at the first
+// problem, it continues to check the rest of the given value so that it can
+// report all of the problems at once.
+//
+// Each of the returned errors is a cty.PathError describing a problem at a
+// particular path within the given value, using the same messages that
+// Convert would return, including those from MismatchMessage for values that
+// have the wrong type. Errors are deduplicated and sorted by path, with
+// object attributes in lexical order and list and tuple elements in index
+// order.
+//
+// If conversion succeeds then the result is the same as for Convert, and the
+// returned slice is nil. Otherwise the returned value is cty.NilVal.
+//
+// ConvertAll reports a problem with a collection or structural value as a
+// whole only if none of its elements have problems of their own, and so
+// some problems may not be reported until the problems with the elements
+// have been fixed. For example, a list value whose elements can't all be
+// converted to the same type is reported only if each element can be
+// converted individually.
+func ConvertAll(in cty.Value, want cty.Type) (cty.Value, []error) {
+	return defaultConverter.convertAll(in, want)
+}
+
+// ConvertAllWithOptions is like ConvertAll except that the given options
+// can disable some of the conversions that ConvertAll would perform, as
+// with ConvertWithOptions.
+func ConvertAllWithOptions(in cty.Value, want cty.Type, opts Options) (cty.Value, []error) {
+	c := &converter{opts: opts}
+	return c.convertAll(in, want)
+}
+
+func (c *converter) convertAll(in cty.Value, want cty.Type) (cty.Value, []error) {
+	ret, err := c.convert(in, want)
+	if err == nil {
+		return ret, nil
+	}
+	errs := c.collectErrors(nil, in, want, err)
+	return cty.NilVal, sortErrors(errs)
+}
+
+// collectErrors returns all of the problems with converting the given value,
+// located at the given path, to the given type. The given error is the
+// result of converting the value as a whole, which is returned if there are
+// no more specific problems with any of the value's elements.
+func (c *converter) collectErrors(path cty.Path, val cty.Value, want cty.Type, err error) []error {
+	val, _ = val.Unmark()
+	var errs []error
+	if val.IsKnown() && !val.IsNull() && want != cty.DynamicPseudoType {
+		errs = c.collectElementErrors(path, val, want)
+	}
+	if len(errs) == 0 {
+		errs = append(errs, path.NewError(err))
+	}
+	return errs
+}
+
+// collectElementErrors returns all of the problems with converting the
+// elements of the given known, non-null, and unmarked value to the
+// corresponding element types of the given type, or nil if the given value
+// and type don't have corresponding elements.
+func (c *converter) collectElementErrors(path cty.Path, val cty.Value, want cty.Type) []error {
+	var errs []error
+	elem := func(step cty.PathStep, v cty.Value, ety cty.Type) {
+		if _, err := c.convert(v, ety); err != nil {
+			elemPath := append(path.Copy(), step)
+			errs = append(errs, c.collectErrors(elemPath, v, ety, err)...)
+		}
+	}
+
+	ty := val.Type()
+	switch {
+	case want.IsObjectType() && (ty.IsObjectType() || ty.IsMapType()):
+		var missing []string
+		for name, aty := range want.AttributeTypes() {
+			if ty.IsObjectType() {
+				if !ty.HasAttribute(name) {
+					if !want.AttributeOptional(name) {
+						missing = append(missing, name)
+					}
+					continue
+				}
+				elem(cty.GetAttrStep{Name: name}, val.GetAttr(name), aty)
+			} else {
+				key := cty.StringVal(name)
+				if !val.HasIndex(key).True() {
+					if !want.AttributeOptional(name) {
+						errs = append(errs, path.NewError(missingMapElementError(ty, want, name)))
+					}
+					continue
+				}
+				elem(cty.IndexStep{Key: key}, val.Index(key), aty)
+			}
+		}
+		if len(missing) != 0 {
+			errs = append(errs, path.NewError(missingAttrsError(ty, want, missing, attributeSuggestions(nil, ty, want))))
+		}
+
+	case want.IsMapType() && (ty.IsMapType() || ty.IsObjectType()):
+		if ty.IsObjectType() && c.opts.DisableObjectToMap {
+			return nil
+		}
+		for it := val.ElementIterator(); it.Next(); {
+			key, v := it.Element()
+			elem(cty.IndexStep{Key: key}, v, want.ElementType())
+		}
+
+	case (want.IsListType() || want.IsSetType()) && (ty.IsListType() || ty.IsSetType() || ty.IsTupleType()):
+		if ty.IsTupleType() && c.opts.DisableTupleToList {
+			return nil
+		}
+		i := int64(0)
+		for it := val.ElementIterator(); it.Next(); i++ {
+			_, v := it.Element()
+			elem(cty.IndexStep{Key: cty.NumberIntVal(i)}, v, want.ElementType())
+		}
+
+	case want.IsTupleType() && ty.IsTupleType() && want.Length() == ty.Length():
+		i := 0
+		for it := val.ElementIterator(); it.Next(); i++ {
+			_, v := it.Element()
+			elem(cty.IndexStep{Key: cty.NumberIntVal(int64(i))}, v, want.TupleElementType(i))
+		}
+	}
+	return errs
+}
+
+// sortErrors deduplicates the given errors and sorts them by path and then
+// by message. Any errors that aren't cty.PathError values are treated as
+// relating to the value as a whole.
+func sortErrors(errs []error) []error {
+	seen := make(map[string]struct{}, len(errs))
+	ret := make([]error, 0, len(errs))
+	for _, err := range errs {
+		key := errorPath(err).String() + "\x00" + err.Error()
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		ret = append(ret, err)
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		pi, pj := errorPath(ret[i]), errorPath(ret[j])
+		if c := comparePaths(pi, pj); c != 0 {
+			return c < 0
+		}
+		return ret[i].Error() < ret[j].Error()
+	})
+	return ret
+}
+
+// errorPath returns the path of the given error if it is a cty.PathError, or
+// nil otherwise.
+func errorPath(err error) cty.Path {
+	var pathErr cty.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Path
+	}
+	return nil
+}
+
+// comparePaths orders paths step by step, with attribute steps before index
+// steps and shorter paths before any longer paths they are a prefix of.
+func comparePaths(a, b cty.Path) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := comparePathSteps(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+func comparePathSteps(a, b cty.PathStep) int {
+	switch a := a.(type) {
+	case cty.GetAttrStep:
+		if b, ok := b.(cty.GetAttrStep); ok {
+			return strings.Compare(a.Name, b.Name)
+		}
+		return -1
+	case cty.IndexStep:
+		b, ok := b.(cty.IndexStep)
+		if !ok {
+			return 1
+		}
+		ak, _ := a.Key.UnmarkDeep()
+		bk, _ := b.Key.UnmarkDeep()
+		if !ak.Type().Equals(bk.Type()) {
+			return strings.Compare(ak.Type().FriendlyName(), bk.Type().FriendlyName())
+		}
+		return cty.Compare(ak, bk)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,241 @@
+package convert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestConvertAll(t *testing.T) {
+	tests := []struct {
+		Value      cty.Value
+		Type       cty.Type
+		Opts       Options
+		Want       cty.Value
+		WantErrors []string
+	}{
+		{
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("1"),
+			}),
+			Type: cty.Object(map[string]cty.Type{
+				"a": cty.Number,
+			}),
+			Want: cty.ObjectVal(map[string]cty.Value{
+				"a": cty.NumberIntVal(1),
+			}),
+		},
+		{
+			Value: cty.StringVal("hello"),
+			Type:  cty.Number,
+			WantErrors: []string{
+				`a number is required`,
+			},
+		},
+		{
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.True,
+				"count": cty.StringVal("many"),
+				"tags":  cty.ListVal([]cty.Value{cty.StringVal("a")}),
+				"nested": cty.ObjectVal(map[string]cty.Value{
+					"enabled": cty.StringVal("yes"),
+				}),
+			}),
+			Type: cty.Object(map[string]cty.Type{
+				"name":  cty.Number,
+				"count": cty.Number,
+				"tags":  cty.Map(cty.String),
+				"nested": cty.Object(map[string]cty.Type{
+					"enabled": cty.Bool,
+					"size":    cty.Number,
+					"kind":    cty.String,
+				}),
+				"id":  cty.String,
+				"env": cty.String,
+			}),
+			WantErrors: []string{
+				`attributes "env" and "id" are required`,
+				`.count: a number is required`,
+				`.name: number required, but have bool`,
+				`.nested: attributes "kind" and "size" are required`,
+				`.nested.enabled: a bool is required`,
+				`.tags: map of string required`,
+			},
+		},
+		{
+			Value: cty.ListVal([]cty.Value{
+				cty.StringVal("1"),
+				cty.StringVal("b"),
+				cty.StringVal("3"),
+				cty.StringVal("d"),
+			}),
+			Type: cty.List(cty.Number),
+			WantErrors: []string{
+				`[cty.NumberIntVal(1)]: a number is required`,
+				`[cty.NumberIntVal(3)]: a number is required`,
+			},
+		},
+		{
+			Value: cty.MapVal(map[string]cty.Value{
+				"a": cty.StringVal("x"),
+				"b": cty.StringVal("2"),
+			}),
+			Type: cty.Object(map[string]cty.Type{
+				"a": cty.Number,
+				"b": cty.Number,
+				"c": cty.Number,
+			}),
+			WantErrors: []string{
+				`map has no element for required attribute "c"`,
+				`[cty.StringVal("a")]: a number is required`,
+			},
+		},
+		{
+			Value: cty.TupleVal([]cty.Value{
+				cty.StringVal("a"),
+				cty.EmptyObjectVal,
+			}),
+			Type: cty.Tuple([]cty.Type{cty.Bool, cty.Bool}),
+			WantErrors: []string{
+				`[cty.NumberIntVal(0)]: a bool is required`,
+				`[cty.NumberIntVal(1)]: bool required, but have object`,
+			},
+		},
+		{
+			// The elements can each be converted to any type, but there's no
+			// single type they can all be converted to, so we report the
+			// problem with the list as a whole.
+			Value: cty.TupleVal([]cty.Value{
+				cty.StringVal("a"),
+				cty.EmptyObjectVal,
+			}),
+			Type: cty.List(cty.DynamicPseudoType),
+			WantErrors: []string{
+				`all list elements must have the same type`,
+			},
+		},
+		{
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("1"),
+				"b": cty.TupleVal([]cty.Value{cty.StringVal("x")}),
+			}),
+			Type: cty.Object(map[string]cty.Type{
+				"a": cty.Number,
+				"b": cty.List(cty.Number),
+			}),
+			Opts: Strict,
+			WantErrors: []string{
+				`.a: number required, but have string`,
+				`.b: list of number required`,
+			},
+		},
+		{
+			Value: cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("x")}).Mark("sensitive"),
+				cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("y")}),
+			}),
+			Type: cty.List(cty.Object(map[string]cty.Type{
+				"a": cty.Number,
+			})),
+			WantErrors: []string{
+				`[cty.NumberIntVal(0)].a: a number is required`,
+				`[cty.NumberIntVal(1)].a: a number is required`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v to %#v", test.Value, test.Type), func(t *testing.T) {
+			got, errs := ConvertAllWithOptions(test.Value, test.Type, test.Opts)
+
+			if test.WantErrors != nil {
+				if got != cty.NilVal {
+					t.Errorf("got result %#v; want cty.NilVal", got)
+				}
+				var gotErrs []string
+				for _, err := range errs {
+					gotErrs = append(gotErrs, errorStrForTesting(err))
+				}
+				if diff := cmp.Diff(test.WantErrors, gotErrs); diff != "" {
+					t.Errorf("wrong errors\n%s", diff)
+				}
+				return
+			}
+
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %#v", errs)
+			}
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestConvertAllMatchesConvert(t *testing.T) {
+	// When there's only one problem, ConvertAll should report exactly the
+	// same error as Convert.
+	tests := []struct {
+		Value cty.Value
+		Type  cty.Type
+	}{
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.ListVal([]cty.Value{cty.StringVal("1"), cty.StringVal("x")}),
+			}),
+			cty.Object(map[string]cty.Type{
+				"a": cty.List(cty.Number),
+			}),
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("1"),
+			}),
+			cty.Object(map[string]cty.Type{
+				"a": cty.Number,
+				"b": cty.Number,
+			}),
+		},
+		{
+			cty.TupleVal([]cty.Value{cty.StringVal("1"), cty.StringVal("x")}),
+			cty.List(cty.Number),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v to %#v", test.Value, test.Type), func(t *testing.T) {
+			_, err := Convert(test.Value, test.Type)
+			if err == nil {
+				t.Fatalf("Convert succeeded; want error")
+			}
+			_, errs := ConvertAll(test.Value, test.Type)
+			if len(errs) != 1 {
+				t.Fatalf("got %d errors; want 1", len(errs))
+			}
+			if got, want := errorStrForTesting(errs[0]), errorStrForTesting(err); got != want {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestSortErrorsWithoutPaths(t *testing.T) {
+	// Errors without paths, such as those a custom conversion rule might
+	// return, sort before errors with paths instead of causing a panic.
+	errs := sortErrors([]error{
+		cty.GetAttrPath("b").NewErrorf("b"),
+		fmt.Errorf("whole"),
+		cty.GetAttrPath("a").NewErrorf("a"),
+		fmt.Errorf("whole"),
+	})
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	want := []string{"whole", "a", "b"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong errors\n%s", diff)
+	}
+}
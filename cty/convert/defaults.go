@@ -0,0 +1,244 @@
+package convert
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Defaults describes default values for some of the optional attributes of
+// the object types within a particular type, for use with
+// ConvertWithDefaults.
+//
+// Create a Defaults using NewDefaults, which checks that each default value
+// is suitable for its attribute. A Defaults value is immutable once created,
+// and so it's safe to use the same Defaults concurrently.
+type Defaults struct {
+	ty   cty.Type
+	root *defaultsNode
+}
+
+// Default is a default value for a single optional attribute, for use with
+// NewDefaults.
+type Default struct {
+	// Path is the path of the attribute within the type given to
+	// NewDefaults, using the conventions of cty.Path.ApplyType and
+	// cty.WalkType. The last step must be a cty.GetAttrStep referring to an
+	// optional attribute.
+	//
+	// A cty.IndexStep into a list, map, or set type applies to all of the
+	// elements of the collection, and so its key is ignored. Conventionally
+	// the key is an unknown value of the appropriate type, as used by
+	// cty.WalkType. A cty.IndexStep into a tuple type must have a known
+	// number key, and applies only to the element with that index.
+	Path cty.Path
+
+	// Value is the default value, which must be convertible to the type of
+	// the attribute and must not be null.
+	Value cty.Value
+}
+
+// defaultsNode is the default values for the object type at a particular
+// path within the type of a Defaults, along with the defaults for any other
+// types nested inside it.
+type defaultsNode struct {
+	// values are the default values for the attributes of the object type
+	// at this node, which are already converted to the attribute types.
+	values map[string]cty.Value
+
+	attrs      map[string]*defaultsNode
+	elems      *defaultsNode
+	tupleElems map[int]*defaultsNode
+}
+
+// NewDefaults returns a Defaults for the given type, which must be the same
+// type later passed to ConvertWithDefaults.
+//
+// Each given default value is converted to the type of its attribute. If
+// any of the given defaults is invalid, because its path doesn't refer to an
+// optional attribute or its value can't be converted to the attribute's
+// type, NewDefaults returns a cty.PathError whose path is the path of the
+// invalid default.
+//
+// Default values are not supported for attributes whose type contains
+// cty.DynamicPseudoType, because the type of the result would then depend on
+// whether a default was used.
+func NewDefaults(ty cty.Type, defaults []Default) (*Defaults, error) {
+	ret := &Defaults{
+		ty:   ty,
+		root: &defaultsNode{},
+	}
+
+	for _, def := range defaults {
+		path := def.Path
+		if len(path) == 0 {
+			return nil, path.NewErrorf("path must refer to an object attribute")
+		}
+		step, ok := path[len(path)-1].(cty.GetAttrStep)
+		if !ok {
+			return nil, path.NewErrorf("path must refer to an object attribute")
+		}
+		objTy, err := path[:len(path)-1].ApplyType(ty)
+		if err != nil {
+			return nil, path.NewError(err)
+		}
+		if !objTy.IsObjectType() || !objTy.HasAttribute(step.Name) {
+			return nil, path.NewErrorf("path must refer to an object attribute")
+		}
+		if !objTy.AttributeOptional(step.Name) {
+			return nil, path.NewErrorf("attribute %q is not optional", step.Name)
+		}
+		aty := objTy.AttributeType(step.Name)
+		if aty.HasDynamicTypes() {
+			return nil, path.NewErrorf("cannot use a default value for an attribute of type %s", aty.FriendlyNameForConstraint())
+		}
+		if def.Value.IsNull() {
+			return nil, path.NewErrorf("default value must not be null")
+		}
+		val, err := Convert(def.Value, aty)
+		if err != nil {
+			return nil, path.NewError(err)
+		}
+
+		node, err := ret.root.node(ty, path[:len(path)-1])
+		if err != nil {
+			return nil, err
+		}
+		if node.values == nil {
+			node.values = make(map[string]cty.Value)
+		}
+		if _, exists := node.values[step.Name]; exists {
+			return nil, path.NewErrorf("duplicate default value for this attribute")
+		}
+		node.values[step.Name] = val
+	}
+
+	return ret, nil
+}
+
+// Type returns the type that the receiver was created for.
+func (d *Defaults) Type() cty.Type {
+	return d.ty
+}
+
+// ConvertWithDefaults is like Convert except that, after converting the
+// given value to the type of the given defaults, it inserts the default
+// values in place of any null values for the optional attributes they
+// belong to.
+//
+// Default values are inserted at any depth, including inside the elements
+// of collections. If the default value for an attribute itself contains
+// optional attributes that have default values then those defaults are
+// inserted into the default value too.
+//
+// Because conversion sets any optional attributes that are absent from the
+// given value to null, default values are used both for attributes that
+// are absent and for attributes that are explicitly set to null.
+func ConvertWithDefaults(in cty.Value, defaults *Defaults) (cty.Value, error) {
+	ret, err := Convert(in, defaults.ty)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return defaults.root.apply(ret), nil
+}
+
+// node returns the node for the type at the given path within the given
+// type, creating it and any intermediate nodes if necessary. The given path
+// must be valid for the given type.
+func (n *defaultsNode) node(ty cty.Type, path cty.Path) (*defaultsNode, error) {
+	for i, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			if n.attrs == nil {
+				n.attrs = make(map[string]*defaultsNode)
+			}
+			if n.attrs[step.Name] == nil {
+				n.attrs[step.Name] = &defaultsNode{}
+			}
+			n = n.attrs[step.Name]
+			ty = ty.AttributeType(step.Name)
+		case cty.IndexStep:
+			if ty.IsTupleType() {
+				key, _ := step.Key.Unmark()
+				if !key.IsKnown() || key.IsNull() {
+					return nil, path[:i+1].NewErrorf("index into a tuple type must be known")
+				}
+				idx, _ := key.AsBigFloat().Int64()
+				if n.tupleElems == nil {
+					n.tupleElems = make(map[int]*defaultsNode)
+				}
+				if n.tupleElems[int(idx)] == nil {
+					n.tupleElems[int(idx)] = &defaultsNode{}
+				}
+				n = n.tupleElems[int(idx)]
+				ty = ty.TupleElementType(int(idx))
+				continue
+			}
+			if n.elems == nil {
+				n.elems = &defaultsNode{}
+			}
+			n = n.elems
+			ty = ty.ElementType()
+		}
+	}
+	return n, nil
+}
+
+// apply returns the given value with the default values of the receiver and
+// its descendents inserted.
+func (n *defaultsNode) apply(val cty.Value) cty.Value {
+	if n == nil {
+		return val
+	}
+	val, marks := val.Unmark()
+	if val.IsNull() || !val.IsKnown() {
+		return val.WithMarks(marks)
+	}
+
+	ty := val.Type()
+	switch {
+	case ty.IsObjectType():
+		attrs := val.AsValueMap()
+		if attrs == nil {
+			return val.WithMarks(marks)
+		}
+		for name, def := range n.values {
+			if av, avMarks := attrs[name].Unmark(); av.IsNull() {
+				attrs[name] = def.WithMarks(avMarks)
+			}
+		}
+		for name, child := range n.attrs {
+			attrs[name] = child.apply(attrs[name])
+		}
+		return cty.ObjectVal(attrs).WithMarks(marks)
+	case ty.IsTupleType():
+		elems := val.AsValueSlice()
+		for i, child := range n.tupleElems {
+			elems[i] = child.apply(elems[i])
+		}
+		if len(elems) == 0 {
+			return val.WithMarks(marks)
+		}
+		return cty.TupleVal(elems).WithMarks(marks)
+	case n.elems == nil || val.LengthInt() == 0:
+		return val.WithMarks(marks)
+	case ty.IsListType():
+		elems := val.AsValueSlice()
+		for i, ev := range elems {
+			elems[i] = n.elems.apply(ev)
+		}
+		return cty.ListVal(elems).WithMarks(marks)
+	case ty.IsSetType():
+		elems := val.AsValueSlice()
+		for i, ev := range elems {
+			elems[i] = n.elems.apply(ev)
+		}
+		return cty.SetVal(elems).WithMarks(marks)
+	case ty.IsMapType():
+		elems := val.AsValueMap()
+		for k, ev := range elems {
+			elems[k] = n.elems.apply(ev)
+		}
+		return cty.MapVal(elems).WithMarks(marks)
+	default:
+		return val.WithMarks(marks)
+	}
+}
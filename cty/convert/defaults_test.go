@@ -0,0 +1,208 @@
+package convert
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestConvertWithDefaults(t *testing.T) {
+	serviceTy := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"name":     cty.String,
+		"port":     cty.Number,
+		"protocol": cty.String,
+		"health": cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+			"path":     cty.String,
+			"interval": cty.Number,
+		}, []string{"path", "interval"}),
+	}, []string{"port", "protocol", "health"})
+	ty := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"services": cty.List(serviceTy),
+		"named":    cty.Map(serviceTy),
+		"primary":  serviceTy,
+		"region":   cty.String,
+	}, []string{"named", "primary", "region"})
+
+	anyService := cty.GetAttrPath("services").Index(cty.UnknownVal(cty.Number))
+	anyNamed := cty.GetAttrPath("named").Index(cty.UnknownVal(cty.String))
+	defaults, err := NewDefaults(ty, []Default{
+		{Path: cty.GetAttrPath("region"), Value: cty.StringVal("us-east-1")},
+		{Path: anyService.GetAttr("port"), Value: cty.StringVal("80")},
+		{Path: anyService.GetAttr("protocol"), Value: cty.StringVal("tcp")},
+		{Path: anyService.GetAttr("health"), Value: cty.EmptyObjectVal},
+		{Path: anyService.GetAttr("health").GetAttr("path"), Value: cty.StringVal("/")},
+		{Path: anyNamed.GetAttr("port"), Value: cty.NumberIntVal(8080)},
+		{Path: cty.GetAttrPath("primary").GetAttr("protocol"), Value: cty.StringVal("udp")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	in := cty.ObjectVal(map[string]cty.Value{
+		"services": cty.TupleVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("web"),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"name":     cty.StringVal("dns"),
+				"port":     cty.NumberIntVal(53),
+				"protocol": cty.NullVal(cty.String),
+				"health": cty.ObjectVal(map[string]cty.Value{
+					"path": cty.StringVal("/healthz"),
+				}),
+			}),
+		}),
+		"named": cty.ObjectVal(map[string]cty.Value{
+			"api": cty.ObjectVal(map[string]cty.Value{
+				"name": cty.StringVal("api").Mark("marked"),
+			}),
+		}),
+		"region": cty.NullVal(cty.String),
+	})
+
+	nullHealth := cty.NullVal(cty.Object(map[string]cty.Type{
+		"path":     cty.String,
+		"interval": cty.Number,
+	}))
+	want := cty.ObjectVal(map[string]cty.Value{
+		"services": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":     cty.StringVal("web"),
+				"port":     cty.NumberIntVal(80),
+				"protocol": cty.StringVal("tcp"),
+				"health": cty.ObjectVal(map[string]cty.Value{
+					"path":     cty.StringVal("/"),
+					"interval": cty.NullVal(cty.Number),
+				}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"name":     cty.StringVal("dns"),
+				"port":     cty.NumberIntVal(53),
+				"protocol": cty.StringVal("tcp"),
+				"health": cty.ObjectVal(map[string]cty.Value{
+					"path":     cty.StringVal("/healthz"),
+					"interval": cty.NullVal(cty.Number),
+				}),
+			}),
+		}),
+		"named": cty.MapVal(map[string]cty.Value{
+			"api": cty.ObjectVal(map[string]cty.Value{
+				"name":     cty.StringVal("api").Mark("marked"),
+				"port":     cty.NumberIntVal(8080),
+				"protocol": cty.NullVal(cty.String),
+				"health":   nullHealth,
+			}),
+		}),
+		"primary": cty.NullVal(serviceTy.WithoutOptionalAttributesDeep()),
+		"region":  cty.StringVal("us-east-1"),
+	})
+
+	got, err := ConvertWithDefaults(in, defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestConvertWithDefaultsTuple(t *testing.T) {
+	elemTy := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"a": cty.String,
+	}, []string{"a"})
+	ty := cty.Tuple([]cty.Type{elemTy, elemTy})
+	defaults, err := NewDefaults(ty, []Default{
+		{Path: cty.IndexIntPath(1).GetAttr("a"), Value: cty.StringVal("second")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	got, err := ConvertWithDefaults(cty.TupleVal([]cty.Value{cty.EmptyObjectVal, cty.EmptyObjectVal}), defaults)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := cty.TupleVal([]cty.Value{
+		cty.ObjectVal(map[string]cty.Value{"a": cty.NullVal(cty.String)}),
+		cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("second")}),
+	})
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	_, err = ConvertWithDefaults(cty.StringVal("nope"), defaults)
+	if err == nil {
+		t.Fatalf("unexpected success with value of wrong type")
+	}
+}
+
+func TestNewDefaultsErrors(t *testing.T) {
+	ty := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"req":   cty.String,
+		"opt":   cty.Number,
+		"any":   cty.DynamicPseudoType,
+		"list":  cty.List(cty.String),
+		"tuple": cty.Tuple([]cty.Type{cty.ObjectWithOptionalAttrs(map[string]cty.Type{"a": cty.String}, []string{"a"})}),
+	}, []string{"opt", "any", "list", "tuple"})
+
+	tests := map[string]struct {
+		Defaults  []Default
+		WantError string
+	}{
+		"empty path": {
+			[]Default{{Path: cty.Path{}, Value: cty.StringVal("a")}},
+			`path must refer to an object attribute`,
+		},
+		"index step": {
+			[]Default{{Path: cty.GetAttrPath("list").Index(cty.UnknownVal(cty.Number)), Value: cty.StringVal("a")}},
+			`.list[cty.UnknownVal(cty.Number)]: path must refer to an object attribute`,
+		},
+		"no such attribute": {
+			[]Default{{Path: cty.GetAttrPath("nope"), Value: cty.StringVal("a")}},
+			`.nope: path must refer to an object attribute`,
+		},
+		"not an object": {
+			[]Default{{Path: cty.GetAttrPath("req").GetAttr("a"), Value: cty.StringVal("a")}},
+			`.req.a: path must refer to an object attribute`,
+		},
+		"required attribute": {
+			[]Default{{Path: cty.GetAttrPath("req"), Value: cty.StringVal("a")}},
+			`.req: attribute "req" is not optional`,
+		},
+		"wrong type": {
+			[]Default{{Path: cty.GetAttrPath("opt"), Value: cty.StringVal("a")}},
+			`.opt: a number is required`,
+		},
+		"null": {
+			[]Default{{Path: cty.GetAttrPath("opt"), Value: cty.NullVal(cty.Number)}},
+			`.opt: default value must not be null`,
+		},
+		"dynamic": {
+			[]Default{{Path: cty.GetAttrPath("any"), Value: cty.StringVal("a")}},
+			`.any: cannot use a default value for an attribute of type any type`,
+		},
+		"duplicate": {
+			[]Default{
+				{Path: cty.GetAttrPath("opt"), Value: cty.NumberIntVal(1)},
+				{Path: cty.GetAttrPath("opt"), Value: cty.NumberIntVal(2)},
+			},
+			`.opt: duplicate default value for this attribute`,
+		},
+		"unknown tuple index": {
+			[]Default{{Path: cty.GetAttrPath("tuple").Index(cty.UnknownVal(cty.Number)).GetAttr("a"), Value: cty.StringVal("a")}},
+			`.tuple[cty.UnknownVal(cty.Number)]: index into a tuple type must be known`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewDefaults(ty, test.Defaults)
+			if err == nil {
+				t.Fatalf("unexpected success")
+			}
+			if got := errorStrForTesting(err); got != test.WantError {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.WantError)
+			}
+		})
+	}
+}
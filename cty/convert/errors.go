@@ -0,0 +1,166 @@
+package convert
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// The error types in this file describe specific kinds of conversion failure,
+// so that callers can react to them programmatically rather than by matching
+// the text of error messages.
+//
+// Errors returned from conversions are often wrapped in a cty.PathError
+// describing where in the given value the problem occurred, and some of these
+// errors wrap others that describe a problem with a nested type, so callers
+// should use errors.As to find them rather than type assertions.
+//
+// In all of these types, Err is the error that describes the problem in
+// English, and its message is also the result of Error.
+
+// MissingAttributeError reports that a value does not have one or more of
+// the attributes that are required by the wanted object type.
+type MissingAttributeError struct {
+	// Names are the names of the missing attributes, in lexical order.
+	Names []string
+
+	// Got is the type of the given value, which is usually an object or
+	// map type. Want is the object type that the value was being converted
+	// to, or cty.NilType if the value was not being converted to a cty type.
+	Got, Want cty.Type
+
+	// Suggestions describes any attributes of the given type that are
+	// likely to be misspellings of the missing attributes.
+	Suggestions []AttributeSuggestion
+
+	Err error
+}
+
+func (e MissingAttributeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e MissingAttributeError) Unwrap() error {
+	return e.Err
+}
+
+// UnsupportedAttributeError reports that a value has an attribute that cannot
+// be represented in the wanted type.
+type UnsupportedAttributeError struct {
+	// Name is the name of the unsupported attribute.
+	Name string
+
+	// Got is the type of the given value. Want is the type that the value
+	// was being converted to, or cty.NilType if the value was not being
+	// converted to a cty type.
+	Got, Want cty.Type
+
+	Err error
+}
+
+func (e UnsupportedAttributeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e UnsupportedAttributeError) Unwrap() error {
+	return e.Err
+}
+
+// ElementTypeError reports that the elements of a value cannot be converted
+// to the element type of the wanted collection type, or cannot be converted
+// to any single type that would be suitable for the wanted collection.
+//
+// If the problem is with a specific element then Err wraps another error
+// describing the problem with that element, which errors.As can find.
+type ElementTypeError struct {
+	// Got is the type of the given value. Want is the collection type that
+	// the value was being converted to.
+	Got, Want cty.Type
+
+	Err error
+}
+
+func (e ElementTypeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e ElementTypeError) Unwrap() error {
+	return e.Err
+}
+
+// LossyNumberError reports that a number cannot be represented in the wanted
+// type without losing information, such as when converting a fractional or
+// out-of-range number to a fixed-size integer.
+type LossyNumberError struct {
+	// Value is the number that could not be represented.
+	Value cty.Value
+
+	// Got is the type of the given value. Want is the type that the value
+	// was being converted to, or cty.NilType if the value was not being
+	// converted to a cty type.
+	Got, Want cty.Type
+
+	Err error
+}
+
+func (e LossyNumberError) Error() string {
+	return e.Err.Error()
+}
+
+func (e LossyNumberError) Unwrap() error {
+	return e.Err
+}
+
+// NullNotAllowedError reports that a value is null where the wanted type
+// cannot represent null.
+type NullNotAllowedError struct {
+	// Got is the type of the given value. Want is the type that the value
+	// was being converted to, or cty.NilType if the value was not being
+	// converted to a cty type.
+	Got, Want cty.Type
+
+	Err error
+}
+
+func (e NullNotAllowedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e NullNotAllowedError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownNotAllowedError reports that a value is unknown where the wanted
+// type cannot represent unknown values.
+type UnknownNotAllowedError struct {
+	// Got is the type of the given value. Want is the type that the value
+	// was being converted to, or cty.NilType if the value was not being
+	// converted to a cty type.
+	Got, Want cty.Type
+
+	Err error
+}
+
+func (e UnknownNotAllowedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e UnknownNotAllowedError) Unwrap() error {
+	return e.Err
+}
+
+// TypeMismatchError reports that a value cannot be converted to the wanted
+// type, in situations not covered by one of the more specific error types.
+type TypeMismatchError struct {
+	// Got is the type of the given value. Want is the type that the value
+	// was being converted to.
+	Got, Want cty.Type
+
+	Err error
+}
+
+func (e TypeMismatchError) Error() string {
+	return e.Err.Error()
+}
+
+func (e TypeMismatchError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,170 @@
+package convert
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestConvertErrorTypes(t *testing.T) {
+	t.Run("missing attribute", func(t *testing.T) {
+		in := cty.ObjectVal(map[string]cty.Value{
+			"instnce_type": cty.StringVal("large"),
+		})
+		want := cty.Object(map[string]cty.Type{
+			"instance_type": cty.String,
+			"count":         cty.Number,
+		})
+		_, err := Convert(in, want)
+
+		var got MissingAttributeError
+		if !errors.As(err, &got) {
+			t.Fatalf("error is not a MissingAttributeError: %#v", err)
+		}
+		if want, got := `attributes "count" and "instance_type" are required; did you mean "instance_type" instead of "instnce_type"?`, err.Error(); got != want {
+			t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+		}
+		if len(got.Names) != 2 || got.Names[0] != "count" || got.Names[1] != "instance_type" {
+			t.Errorf("wrong names %#v", got.Names)
+		}
+		if !got.Got.Equals(in.Type()) || !got.Want.Equals(want) {
+			t.Errorf("wrong types\ngot:  %#v\nwant: %#v", got.Got, got.Want)
+		}
+		if len(got.Suggestions) != 1 || got.Suggestions[0].Got != "instnce_type" {
+			t.Errorf("wrong suggestions %#v", got.Suggestions)
+		}
+	})
+	t.Run("missing map element", func(t *testing.T) {
+		in := cty.MapVal(map[string]cty.Value{
+			"a": cty.StringVal("a"),
+		})
+		want := cty.Object(map[string]cty.Type{
+			"a": cty.String,
+			"b": cty.String,
+		})
+		_, err := Convert(in, want)
+
+		var got MissingAttributeError
+		if !errors.As(err, &got) {
+			t.Fatalf("error is not a MissingAttributeError: %#v", err)
+		}
+		if want, got := `map has no element for required attribute "b"`, err.Error(); got != want {
+			t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+		}
+		if len(got.Names) != 1 || got.Names[0] != "b" {
+			t.Errorf("wrong names %#v", got.Names)
+		}
+		if !got.Got.Equals(in.Type()) || !got.Want.Equals(want) {
+			t.Errorf("wrong types\ngot:  %#v\nwant: %#v", got.Got, got.Want)
+		}
+	})
+	t.Run("nested in element type", func(t *testing.T) {
+		in := cty.ListVal([]cty.Value{cty.EmptyObjectVal})
+		want := cty.List(cty.Object(map[string]cty.Type{
+			"foo": cty.String,
+		}))
+		_, err := Convert(in, want)
+
+		var elemErr ElementTypeError
+		if !errors.As(err, &elemErr) {
+			t.Fatalf("error is not an ElementTypeError: %#v", err)
+		}
+		if !elemErr.Got.Equals(in.Type()) || !elemErr.Want.Equals(want) {
+			t.Errorf("wrong types\ngot:  %#v\nwant: %#v", elemErr.Got, elemErr.Want)
+		}
+		var attrErr MissingAttributeError
+		if !errors.As(err, &attrErr) {
+			t.Fatalf("error does not wrap a MissingAttributeError: %#v", err)
+		}
+		if !attrErr.Got.Equals(cty.EmptyObject) || !attrErr.Want.Equals(want.ElementType()) {
+			t.Errorf("wrong types\ngot:  %#v\nwant: %#v", attrErr.Got, attrErr.Want)
+		}
+		if want, got := `incorrect list element type: attribute "foo" is required`, err.Error(); got != want {
+			t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+	t.Run("nested in attribute", func(t *testing.T) {
+		in := cty.ObjectVal(map[string]cty.Value{
+			"foo": cty.True,
+		})
+		want := cty.Object(map[string]cty.Type{
+			"foo": cty.Number,
+		})
+		_, err := Convert(in, want)
+
+		var got TypeMismatchError
+		if !errors.As(err, &got) {
+			t.Fatalf("error is not a TypeMismatchError: %#v", err)
+		}
+		if !got.Got.Equals(cty.Bool) || !got.Want.Equals(cty.Number) {
+			t.Errorf("wrong types\ngot:  %#v\nwant: %#v", got.Got, got.Want)
+		}
+		if want, got := `attribute "foo": number required, but have bool`, err.Error(); got != want {
+			t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+	t.Run("element types must match", func(t *testing.T) {
+		in := cty.TupleVal([]cty.Value{
+			cty.StringVal("a"),
+			cty.ListValEmpty(cty.String),
+		})
+		want := cty.List(cty.DynamicPseudoType)
+		_, err := Convert(in, want)
+
+		var got ElementTypeError
+		if !errors.As(err, &got) {
+			t.Fatalf("error is not an ElementTypeError: %#v", err)
+		}
+		if !got.Got.Equals(in.Type()) || !got.Want.Equals(want) {
+			t.Errorf("wrong types\ngot:  %#v\nwant: %#v", got.Got, got.Want)
+		}
+		if want, got := `all list elements must have the same type`, err.Error(); got != want {
+			t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+	t.Run("invalid number string", func(t *testing.T) {
+		in := cty.ListVal([]cty.Value{cty.StringVal("1"), cty.StringVal("nope")})
+		_, err := Convert(in, cty.List(cty.Number))
+
+		var got TypeMismatchError
+		if !errors.As(err, &got) {
+			t.Fatalf("error is not a TypeMismatchError: %#v", err)
+		}
+		if !got.Got.Equals(cty.String) || !got.Want.Equals(cty.Number) {
+			t.Errorf("wrong types\ngot:  %#v\nwant: %#v", got.Got, got.Want)
+		}
+		var pathErr cty.PathError
+		if !errors.As(err, &pathErr) {
+			t.Fatalf("error is not a cty.PathError: %#v", err)
+		}
+		if want, got := `[1]`, pathErr.Path.String(); got != want {
+			t.Errorf("wrong path %s; want %s", got, want)
+		}
+		if want, got := `a number is required`, err.Error(); got != want {
+			t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+		}
+	})
+	t.Run("ConvertAll", func(t *testing.T) {
+		in := cty.ObjectVal(map[string]cty.Value{
+			"a": cty.StringVal("nope"),
+		})
+		want := cty.Object(map[string]cty.Type{
+			"a": cty.Number,
+			"b": cty.String,
+		})
+		_, errs := ConvertAll(in, want)
+		if len(errs) != 2 {
+			t.Fatalf("wrong number of errors %d; want 2", len(errs))
+		}
+
+		var attrErr MissingAttributeError
+		if !errors.As(errs[0], &attrErr) {
+			t.Errorf("first error is not a MissingAttributeError: %#v", errs[0])
+		}
+		var mismatchErr TypeMismatchError
+		if !errors.As(errs[1], &mismatchErr) {
+			t.Errorf("second error is not a TypeMismatchError: %#v", errs[1])
+		}
+	})
+}
@@ -2,6 +2,7 @@ package convert
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"sort"
 
@@ -25,44 +26,70 @@ import (
 // describing conversion failures and so the messages it generates relate
 // specifically to the conversion rules implemented in this package.
 func MismatchMessage(got, want cty.Type) string {
+	return defaultConverter.mismatchMessage(got, want)
+}
+
+func (c *converter) mismatchMessage(got, want cty.Type) string {
+	return c.mismatchError(got, want).Error()
+}
+
+// mismatchError is like mismatchMessage except that it returns an error of
+// one of the types defined in this package, whose message is the one that
+// mismatchMessage would return.
+func (c *converter) mismatchError(got, want cty.Type) error {
+	if err := c.ruleMismatchError(got, want); err != nil {
+		return err
+	}
+
 	switch {
 
+	case got.IsTupleType() && want.IsCollectionType() && c.opts.DisableTupleToList,
+		got.IsObjectType() && want.IsMapType() && c.opts.DisableObjectToMap:
+		// The conversion between these kinds of type is disabled altogether,
+		// so there's nothing useful to say about the elements.
+		return typeMismatchError(got, want, want.FriendlyNameForConstraint()+" required")
+
 	case got.IsObjectType() && want.IsObjectType():
 		// If both types are object types then we may be able to say something
 		// about their respective attributes.
-		return mismatchMessageObjects(got, want)
+		return c.mismatchErrorObjects(got, want)
 
 	case got.IsTupleType() && want.IsListType() && want.ElementType() == cty.DynamicPseudoType:
 		// If conversion from tuple to list failed then it's because we couldn't
 		// find a common type to convert all of the tuple elements to.
-		return "all list elements must have the same type"
+		return elementTypeError(got, want, errors.New("all list elements must have the same type"))
 
 	case got.IsTupleType() && want.IsSetType() && want.ElementType() == cty.DynamicPseudoType:
 		// If conversion from tuple to set failed then it's because we couldn't
 		// find a common type to convert all of the tuple elements to.
-		return "all set elements must have the same type"
+		return elementTypeError(got, want, errors.New("all set elements must have the same type"))
 
 	case got.IsObjectType() && want.IsMapType() && want.ElementType() == cty.DynamicPseudoType:
 		// If conversion from object to map failed then it's because we couldn't
 		// find a common type to convert all of the object attributes to.
-		return "all map elements must have the same type"
+		return elementTypeError(got, want, errors.New("all map elements must have the same type"))
 
 	case (got.IsTupleType() || got.IsObjectType()) && want.IsCollectionType():
-		return mismatchMessageCollectionsFromStructural(got, want)
+		return c.mismatchErrorCollectionsFromStructural(got, want)
 
 	case got.IsCollectionType() && want.IsCollectionType():
-		return mismatchMessageCollectionsFromCollections(got, want)
+		return c.mismatchErrorCollectionsFromCollections(got, want)
+
+	case c.primitiveConversionDisabled(got, want):
+		// The rules for a disabled conversion are not subtle, so describing
+		// both types is helpful rather than confusing.
+		return typeMismatchError(got, want, fmt.Sprintf("%s required, but have %s", want.FriendlyName(), got.FriendlyName()))
 
 	case !typesAreLikelyToCauseConfusion(got, want):
-		return fmt.Sprintf("%s required, but have %s", want.FriendlyName(), got.FriendlyName())
+		return typeMismatchError(got, want, fmt.Sprintf("%s required, but have %s", want.FriendlyName(), got.FriendlyName()))
 
 	default:
 		// If we have nothing better to say, we'll just state what was required.
-		return want.FriendlyNameForConstraint() + " required"
+		return typeMismatchError(got, want, want.FriendlyNameForConstraint()+" required")
 	}
 }
 
-func mismatchMessageObjects(got, want cty.Type) string {
+func (c *converter) mismatchErrorObjects(got, want cty.Type) error {
 	// Per our conversion rules, "got" is allowed to be a superset of "want",
 	// and so we'll produce error messages here under that assumption.
 	gotAtys := got.AttributeTypes()
@@ -75,8 +102,8 @@ func mismatchMessageObjects(got, want cty.Type) string {
 	// conversion over a safe one, because these are subjectively more
 	// "serious".
 	var missingAttrs []string
-	var unsafeMismatchAttr string
-	var safeMismatchAttr string
+	var unsafeMismatchAttr error
+	var safeMismatchAttr error
 
 	for name, wantAty := range wantAtys {
 		gotAty, exists := gotAtys[name]
@@ -98,20 +125,20 @@ func mismatchMessageObjects(got, want cty.Type) string {
 
 		// If we already have an unsafe mismatch attr error then we won't bother
 		// hunting for another one.
-		if unsafeMismatchAttr != "" {
+		if unsafeMismatchAttr != nil {
 			continue
 		}
-		if conv := GetConversionUnsafe(gotAty, wantAty); conv == nil {
-			unsafeMismatchAttr = fmt.Sprintf("attribute %q: %s", name, MismatchMessage(gotAty, wantAty))
+		if conv := c.getConversion(gotAty, wantAty, true); conv == nil {
+			unsafeMismatchAttr = fmt.Errorf("attribute %q: %w", name, c.mismatchError(gotAty, wantAty))
 		}
 
 		// If we already have a safe mismatch attr error then we won't bother
 		// hunting for another one.
-		if safeMismatchAttr != "" {
+		if safeMismatchAttr != nil {
 			continue
 		}
-		if conv := GetConversion(gotAty, wantAty); conv == nil {
-			safeMismatchAttr = fmt.Sprintf("attribute %q: %s", name, MismatchMessage(gotAty, wantAty))
+		if conv := c.getConversion(gotAty, wantAty, false); conv == nil {
+			safeMismatchAttr = fmt.Errorf("attribute %q: %w", name, c.mismatchError(gotAty, wantAty))
 		}
 	}
 
@@ -121,44 +148,79 @@ func mismatchMessageObjects(got, want cty.Type) string {
 	switch {
 
 	case len(missingAttrs) != 0:
-		sort.Strings(missingAttrs)
-		switch len(missingAttrs) {
-		case 1:
-			return fmt.Sprintf("attribute %q is required", missingAttrs[0])
-		case 2:
-			return fmt.Sprintf("attributes %q and %q are required", missingAttrs[0], missingAttrs[1])
-		default:
-			sort.Strings(missingAttrs)
-			var buf bytes.Buffer
-			for _, name := range missingAttrs[:len(missingAttrs)-1] {
-				fmt.Fprintf(&buf, "%q, ", name)
-			}
-			fmt.Fprintf(&buf, "and %q", missingAttrs[len(missingAttrs)-1])
-			return fmt.Sprintf("attributes %s are required", buf.Bytes())
-		}
+		return missingAttrsError(got, want, missingAttrs, attributeSuggestions(nil, got, want))
 
-	case unsafeMismatchAttr != "":
+	case unsafeMismatchAttr != nil:
 		return unsafeMismatchAttr
 
-	case safeMismatchAttr != "":
+	case safeMismatchAttr != nil:
 		return safeMismatchAttr
 
 	default:
 		// We should never get here, but if we do then we'll return
 		// just a generic message.
-		return "incorrect object attributes"
+		return typeMismatchError(got, want, "incorrect object attributes")
 	}
 }
 
-func mismatchMessageCollectionsFromStructural(got, want cty.Type) string {
+// missingAttrsError returns a MissingAttributeError reporting that the given
+// attributes are required, along with any of the given suggestions that are
+// for required attributes. The given slice of names must not be empty, and
+// will be sorted in place.
+func missingAttrsError(got, want cty.Type, missingAttrs []string, suggestions []AttributeSuggestion) error {
+	var required []AttributeSuggestion
+	for _, s := range suggestions {
+		if !s.Optional {
+			required = append(required, s)
+		}
+	}
+	suggestion := suggestionsMessage(required)
+
+	sort.Strings(missingAttrs)
+	var msg string
+	switch len(missingAttrs) {
+	case 1:
+		msg = fmt.Sprintf("attribute %q is required%s", missingAttrs[0], suggestion)
+	case 2:
+		msg = fmt.Sprintf("attributes %q and %q are required%s", missingAttrs[0], missingAttrs[1], suggestion)
+	default:
+		var buf bytes.Buffer
+		for _, name := range missingAttrs[:len(missingAttrs)-1] {
+			fmt.Fprintf(&buf, "%q, ", name)
+		}
+		fmt.Fprintf(&buf, "and %q", missingAttrs[len(missingAttrs)-1])
+		msg = fmt.Sprintf("attributes %s are required%s", buf.Bytes(), suggestion)
+	}
+	return MissingAttributeError{
+		Names:       missingAttrs,
+		Got:         got,
+		Want:        want,
+		Suggestions: required,
+		Err:         errors.New(msg),
+	}
+}
+
+// missingMapElementError returns a MissingAttributeError reporting that a
+// map being converted to an object type has no element for the given
+// required attribute.
+func missingMapElementError(got, want cty.Type, name string) error {
+	return MissingAttributeError{
+		Names: []string{name},
+		Got:   got,
+		Want:  want,
+		Err:   fmt.Errorf("map has no element for required attribute %q", name),
+	}
+}
+
+func (c *converter) mismatchErrorCollectionsFromStructural(got, want cty.Type) error {
 	// First some straightforward cases where the kind is just altogether wrong.
 	switch {
 	case want.IsListType() && !got.IsTupleType():
-		return want.FriendlyNameForConstraint() + " required"
+		return typeMismatchError(got, want, want.FriendlyNameForConstraint()+" required")
 	case want.IsSetType() && !got.IsTupleType():
-		return want.FriendlyNameForConstraint() + " required"
+		return typeMismatchError(got, want, want.FriendlyNameForConstraint()+" required")
 	case want.IsMapType() && !got.IsObjectType():
-		return want.FriendlyNameForConstraint() + " required"
+		return typeMismatchError(got, want, want.FriendlyNameForConstraint()+" required")
 	}
 
 	// If the kinds are matched well enough then we'll move on to checking
@@ -170,47 +232,47 @@ func mismatchMessageCollectionsFromStructural(got, want cty.Type) string {
 			if gotEty.Equals(wantEty) {
 				continue // exact match, so no problem
 			}
-			if conv := getConversion(gotEty, wantEty, true); conv != nil {
+			if conv := c.getConversion(gotEty, wantEty, true); conv != nil {
 				continue // conversion is available, so no problem
 			}
-			return fmt.Sprintf("element %d: %s", i, MismatchMessage(gotEty, wantEty))
+			return elementTypeError(got, want, fmt.Errorf("element %d: %w", i, c.mismatchError(gotEty, wantEty)))
 		}
 
 		// If we get down here then something weird is going on but we'll
 		// return a reasonable fallback message anyway.
-		return fmt.Sprintf("all elements must be %s", wantEty.FriendlyNameForConstraint())
+		return elementTypeError(got, want, fmt.Errorf("all elements must be %s", wantEty.FriendlyNameForConstraint()))
 
 	case got.IsObjectType():
 		for name, gotAty := range got.AttributeTypes() {
 			if gotAty.Equals(wantEty) {
 				continue // exact match, so no problem
 			}
-			if conv := getConversion(gotAty, wantEty, true); conv != nil {
+			if conv := c.getConversion(gotAty, wantEty, true); conv != nil {
 				continue // conversion is available, so no problem
 			}
-			return fmt.Sprintf("element %q: %s", name, MismatchMessage(gotAty, wantEty))
+			return elementTypeError(got, want, fmt.Errorf("element %q: %w", name, c.mismatchError(gotAty, wantEty)))
 		}
 
 		// If we get down here then something weird is going on but we'll
 		// return a reasonable fallback message anyway.
-		return fmt.Sprintf("all elements must be %s", wantEty.FriendlyNameForConstraint())
+		return elementTypeError(got, want, fmt.Errorf("all elements must be %s", wantEty.FriendlyNameForConstraint()))
 
 	default:
 		// Should not be possible to get here since we only call this function
 		// with got as structural types, but...
-		return want.FriendlyNameForConstraint() + " required"
+		return typeMismatchError(got, want, want.FriendlyNameForConstraint()+" required")
 	}
 }
 
-func mismatchMessageCollectionsFromCollections(got, want cty.Type) string {
+func (c *converter) mismatchErrorCollectionsFromCollections(got, want cty.Type) error {
 	// First some straightforward cases where the kind is just altogether wrong.
 	switch {
 	case want.IsListType() && !(got.IsListType() || got.IsSetType()):
-		return want.FriendlyNameForConstraint() + " required"
+		return typeMismatchError(got, want, want.FriendlyNameForConstraint()+" required")
 	case want.IsSetType() && !(got.IsListType() || got.IsSetType()):
-		return want.FriendlyNameForConstraint() + " required"
+		return typeMismatchError(got, want, want.FriendlyNameForConstraint()+" required")
 	case want.IsMapType() && !got.IsMapType():
-		return want.FriendlyNameForConstraint() + " required"
+		return typeMismatchError(got, want, want.FriendlyNameForConstraint()+" required")
 	}
 
 	// If the kinds are matched well enough then we'll check the element types.
@@ -225,7 +287,15 @@ func mismatchMessageCollectionsFromCollections(got, want cty.Type) string {
 	case want.IsMapType():
 		noun = "map element type"
 	}
-	return fmt.Sprintf("incorrect %s: %s", noun, MismatchMessage(gotEty, wantEty))
+	return elementTypeError(got, want, fmt.Errorf("incorrect %s: %w", noun, c.mismatchError(gotEty, wantEty)))
+}
+
+func typeMismatchError(got, want cty.Type, msg string) error {
+	return TypeMismatchError{Got: got, Want: want, Err: errors.New(msg)}
+}
+
+func elementTypeError(got, want cty.Type, err error) error {
+	return ElementTypeError{Got: got, Want: want, Err: err}
 }
 
 func typesAreLikelyToCauseConfusion(got, want cty.Type) bool {
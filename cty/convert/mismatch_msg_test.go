@@ -122,6 +122,47 @@ func TestMismatchMessage(t *testing.T) {
 			}),
 			`attribute "baz": attribute "beep" is required`,
 		},
+		{
+			cty.Object(map[string]cty.Type{
+				"instnce_type": cty.String,
+			}),
+			cty.Object(map[string]cty.Type{
+				"instance_type": cty.String,
+			}),
+			`attribute "instance_type" is required; did you mean "instance_type" instead of "instnce_type"?`,
+		},
+		{
+			cty.Object(map[string]cty.Type{
+				"instnce_type": cty.String,
+				"nme":          cty.String,
+				"unrelated":    cty.String,
+			}),
+			cty.Object(map[string]cty.Type{
+				"instance_type": cty.String,
+				"name":          cty.String,
+				"count":         cty.Number,
+			}),
+			`attributes "count", "instance_type", and "name" are required; did you mean "instance_type" instead of "instnce_type" and "name" instead of "nme"?`,
+		},
+		{
+			cty.Object(map[string]cty.Type{
+				"nme": cty.String,
+			}),
+			cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"name":  cty.String,
+				"count": cty.Number,
+			}, []string{"name"}),
+			`attribute "count" is required`,
+		},
+		{
+			cty.List(cty.Object(map[string]cty.Type{
+				"instnce_type": cty.String,
+			})),
+			cty.List(cty.Object(map[string]cty.Type{
+				"instance_type": cty.String,
+			})),
+			`incorrect list element type: attribute "instance_type" is required; did you mean "instance_type" instead of "instnce_type"?`,
+		},
 	}
 
 	for _, test := range tests {
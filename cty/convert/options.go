@@ -0,0 +1,107 @@
+package convert
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Options customizes the behavior of the variants of the functions in this
+// package whose names end in WithOptions.
+//
+// Each field disables one of the automatic conversions that this package
+// would otherwise perform, which can be useful for applications that want
+// to treat the corresponding mismatches as errors, such as when validating
+// input from a strongly-typed serialization format. The zero value of
+// Options disables nothing, and so selects the same behavior as the
+// functions that don't take options.
+type Options struct {
+	// DisableStringNumber disables the conversions from string to number
+	// and from number to string.
+	DisableStringNumber bool
+
+	// DisableStringBool disables the conversions from string to bool and
+	// from bool to string.
+	DisableStringBool bool
+
+	// DisableTupleToList disables the conversions from tuple types to list
+	// and set types, so that only a list or set value can be used where a
+	// list or set is required.
+	DisableTupleToList bool
+
+	// DisableObjectToMap disables the conversion from object types to map
+	// types, so that only a map value can be used where a map is required.
+	DisableObjectToMap bool
+}
+
+// Strict is an Options value that disables all of the conversions that
+// can be disabled, so that conversion succeeds only when a value already
+// has the same kind of type as required, aside from the conversions between
+// lists and sets and the other conversions that cannot be disabled.
+var Strict = Options{
+	DisableStringNumber: true,
+	DisableStringBool:   true,
+	DisableTupleToList:  true,
+	DisableObjectToMap:  true,
+}
+
+// ConvertWithOptions is like Convert except that the given options can
+// disable some of the conversions that Convert would perform. Any error
+// message describes the mismatch in terms of the remaining conversions.
+func ConvertWithOptions(in cty.Value, want cty.Type, opts Options) (cty.Value, error) {
+	c := &converter{opts: opts}
+	return c.convert(in, want)
+}
+
+// GetConversionWithOptions is like GetConversion except that the given
+// options can disable some of the conversions that GetConversion would
+// return, including conversions nested inside collection and structural
+// types.
+func GetConversionWithOptions(in cty.Type, out cty.Type, opts Options) Conversion {
+	c := &converter{opts: opts}
+	return retConversion(c.getConversion(in, out, false))
+}
+
+// GetConversionUnsafeWithOptions is like GetConversionUnsafe except that the
+// given options can disable some of the conversions that GetConversionUnsafe
+// would return, including conversions nested inside collection and
+// structural types.
+func GetConversionUnsafeWithOptions(in cty.Type, out cty.Type, opts Options) Conversion {
+	c := &converter{opts: opts}
+	return retConversion(c.getConversion(in, out, true))
+}
+
+// UnifyWithOptions is like Unify except that it considers only the
+// conversions allowed by the given options, so that the result is
+// consistent with GetConversionWithOptions given the same options.
+func UnifyWithOptions(types []cty.Type, opts Options) (cty.Type, []Conversion) {
+	c := &converter{opts: opts}
+	return c.unify(types, false)
+}
+
+// UnifyUnsafeWithOptions is like UnifyUnsafe except that it considers only
+// the conversions allowed by the given options, so that the result is
+// consistent with GetConversionUnsafeWithOptions given the same options.
+func UnifyUnsafeWithOptions(types []cty.Type, opts Options) (cty.Type, []Conversion) {
+	c := &converter{opts: opts}
+	return c.unify(types, true)
+}
+
+// MismatchMessageWithOptions is like MismatchMessage except that it
+// describes the differences between the two types in terms of only the
+// conversions allowed by the given options.
+func MismatchMessageWithOptions(got, want cty.Type, opts Options) string {
+	c := &converter{opts: opts}
+	return c.mismatchMessage(got, want)
+}
+
+// primitiveConversionDisabled returns true if the options disable the
+// conversion between the given primitive types.
+func (c *converter) primitiveConversionDisabled(in, out cty.Type) bool {
+	switch {
+	case c.opts.DisableStringNumber && (in == cty.String && out == cty.Number || in == cty.Number && out == cty.String):
+		return true
+	case c.opts.DisableStringBool && (in == cty.String && out == cty.Bool || in == cty.Bool && out == cty.String):
+		return true
+	default:
+		return false
+	}
+}
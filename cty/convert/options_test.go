@@ -0,0 +1,245 @@
+package convert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestConvertWithOptions(t *testing.T) {
+	tests := []struct {
+		Value     cty.Value
+		Type      cty.Type
+		Opts      Options
+		Want      cty.Value
+		WantError string
+	}{
+		{
+			Value: cty.StringVal("12"),
+			Type:  cty.Number,
+			Opts:  Options{},
+			Want:  cty.NumberIntVal(12),
+		},
+		{
+			Value:     cty.StringVal("12"),
+			Type:      cty.Number,
+			Opts:      Options{DisableStringNumber: true},
+			WantError: `number required, but have string`,
+		},
+		{
+			Value:     cty.NumberIntVal(12),
+			Type:      cty.String,
+			Opts:      Options{DisableStringNumber: true},
+			WantError: `string required, but have number`,
+		},
+		{
+			Value: cty.StringVal("true"),
+			Type:  cty.Bool,
+			Opts:  Options{DisableStringNumber: true},
+			Want:  cty.True,
+		},
+		{
+			Value:     cty.StringVal("true"),
+			Type:      cty.Bool,
+			Opts:      Options{DisableStringBool: true},
+			WantError: `bool required, but have string`,
+		},
+		{
+			Value:     cty.True,
+			Type:      cty.String,
+			Opts:      Options{DisableStringBool: true},
+			WantError: `string required, but have bool`,
+		},
+		{
+			Value: cty.TupleVal([]cty.Value{cty.StringVal("a")}),
+			Type:  cty.List(cty.String),
+			Opts:  Options{DisableObjectToMap: true},
+			Want:  cty.ListVal([]cty.Value{cty.StringVal("a")}),
+		},
+		{
+			Value:     cty.TupleVal([]cty.Value{cty.StringVal("a")}),
+			Type:      cty.List(cty.String),
+			Opts:      Options{DisableTupleToList: true},
+			WantError: `list of string required`,
+		},
+		{
+			Value:     cty.TupleVal([]cty.Value{cty.StringVal("a")}),
+			Type:      cty.Set(cty.String),
+			Opts:      Options{DisableTupleToList: true},
+			WantError: `set of string required`,
+		},
+		{
+			Value: cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			Type:  cty.Set(cty.String),
+			Opts:  Strict,
+			Want:  cty.SetVal([]cty.Value{cty.StringVal("a")}),
+		},
+		{
+			Value:     cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("b")}),
+			Type:      cty.Map(cty.String),
+			Opts:      Options{DisableObjectToMap: true},
+			WantError: `map of string required`,
+		},
+		{
+			Value: cty.MapVal(map[string]cty.Value{"a": cty.StringVal("b")}),
+			Type:  cty.Object(map[string]cty.Type{"a": cty.String}),
+			Opts:  Strict,
+			Want:  cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("b")}),
+		},
+		{
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.StringVal("web"),
+				"count": cty.StringVal("2"),
+			}),
+			Type: cty.Object(map[string]cty.Type{
+				"name":  cty.String,
+				"count": cty.Number,
+			}),
+			Opts:      Strict,
+			WantError: `attribute "count": number required, but have string`,
+		},
+		{
+			Value: cty.ListVal([]cty.Value{cty.StringVal("1")}),
+			Type:  cty.List(cty.Number),
+			Opts:  Strict,
+			// The message for nested mismatches is generated by
+			// MismatchMessageWithOptions, with the same options.
+			WantError: `incorrect list element type: number required, but have string`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v to %#v with %#v", test.Value, test.Type, test.Opts), func(t *testing.T) {
+			got, err := ConvertWithOptions(test.Value, test.Type, test.Opts)
+
+			if test.WantError != "" {
+				if err == nil {
+					t.Fatalf("conversion succeeded with %#v; want error", got)
+				}
+				if gotErr := errorStrForTesting(err); gotErr != test.WantError {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", gotErr, test.WantError)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestGetConversionWithOptions(t *testing.T) {
+	tests := []struct {
+		In, Out    cty.Type
+		Opts       Options
+		WantSafe   bool
+		WantUnsafe bool
+	}{
+		{cty.Number, cty.String, Options{}, true, true},
+		{cty.Number, cty.String, Options{DisableStringNumber: true}, false, false},
+		{cty.String, cty.Number, Options{}, false, true},
+		{cty.String, cty.Number, Options{DisableStringNumber: true}, false, false},
+		{cty.String, cty.Number, Options{DisableStringBool: true}, false, true},
+		{cty.Bool, cty.String, Options{DisableStringBool: true}, false, false},
+		{cty.EmptyTuple, cty.List(cty.String), Options{DisableTupleToList: true}, false, false},
+		{cty.EmptyObject, cty.Map(cty.String), Options{DisableObjectToMap: true}, false, false},
+		{cty.List(cty.Number), cty.List(cty.String), Strict, false, false},
+		{cty.List(cty.Number), cty.Set(cty.Number), Strict, false, true},
+		{cty.DynamicPseudoType, cty.String, Strict, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v to %#v with %#v", test.In, test.Out, test.Opts), func(t *testing.T) {
+			if got := GetConversionWithOptions(test.In, test.Out, test.Opts) != nil; got != test.WantSafe {
+				t.Errorf("wrong safe result %t; want %t", got, test.WantSafe)
+			}
+			if got := GetConversionUnsafeWithOptions(test.In, test.Out, test.Opts) != nil; got != test.WantUnsafe {
+				t.Errorf("wrong unsafe result %t; want %t", got, test.WantUnsafe)
+			}
+		})
+	}
+}
+
+func TestUnifyWithOptions(t *testing.T) {
+	tests := []struct {
+		Input []cty.Type
+		Opts  Options
+		Want  cty.Type
+	}{
+		{
+			[]cty.Type{cty.Number, cty.String},
+			Options{},
+			cty.String,
+		},
+		{
+			[]cty.Type{cty.Number, cty.String},
+			Options{DisableStringNumber: true},
+			cty.NilType,
+		},
+		{
+			[]cty.Type{cty.Bool, cty.String},
+			Options{DisableStringNumber: true},
+			cty.String,
+		},
+		{
+			[]cty.Type{cty.List(cty.String), cty.Tuple([]cty.Type{cty.String})},
+			Options{},
+			cty.List(cty.String),
+		},
+		{
+			[]cty.Type{cty.List(cty.String), cty.Tuple([]cty.Type{cty.String})},
+			Options{DisableTupleToList: true},
+			cty.NilType,
+		},
+		{
+			[]cty.Type{
+				cty.Object(map[string]cty.Type{"a": cty.String}),
+				cty.Object(map[string]cty.Type{"b": cty.String}),
+			},
+			Options{},
+			cty.Map(cty.String),
+		},
+		{
+			[]cty.Type{
+				cty.Object(map[string]cty.Type{"a": cty.String}),
+				cty.Object(map[string]cty.Type{"b": cty.String}),
+			},
+			Options{DisableObjectToMap: true},
+			cty.NilType,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v with %#v", test.Input, test.Opts), func(t *testing.T) {
+			for _, unsafe := range []bool{false, true} {
+				var got cty.Type
+				var convs []Conversion
+				if unsafe {
+					got, convs = UnifyUnsafeWithOptions(test.Input, test.Opts)
+				} else {
+					got, convs = UnifyWithOptions(test.Input, test.Opts)
+				}
+				if got == cty.NilType && test.Want == cty.NilType {
+					continue
+				}
+				if got == cty.NilType || !got.Equals(test.Want) {
+					t.Errorf("wrong result type (unsafe=%t)\ngot:  %#v\nwant: %#v", unsafe, got, test.Want)
+					continue
+				}
+				for i, conv := range convs {
+					if conv == nil {
+						continue
+					}
+					if GetConversionUnsafeWithOptions(test.Input[i], got, test.Opts) == nil {
+						t.Errorf("unify chose a conversion for input %d that GetConversionUnsafeWithOptions rejects", i)
+					}
+				}
+			}
+		})
+	}
+}
@@ -1,8 +1,6 @@
 package convert
 
 import (
-	"errors"
-
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -24,13 +22,13 @@ type Conversion func(in cty.Value) (out cty.Value, err error)
 // GetConversion returns a Conversion between the given in and out Types if
 // a safe one is available, or returns nil otherwise.
 func GetConversion(in cty.Type, out cty.Type) Conversion {
-	return retConversion(getConversion(in, out, false))
+	return retConversion(defaultConverter.getConversion(in, out, false))
 }
 
 // GetConversionUnsafe returns a Conversion between the given in and out Types
 // if either a safe or unsafe one is available, or returns nil otherwise.
 func GetConversionUnsafe(in cty.Type, out cty.Type) Conversion {
-	return retConversion(getConversion(in, out, true))
+	return retConversion(defaultConverter.getConversion(in, out, true))
 }
 
 // Convert returns the result of converting the given value to the given type
@@ -40,15 +38,7 @@ func GetConversionUnsafe(in cty.Type, out cty.Type) Conversion {
 // This is a convenience wrapper around calling GetConversionUnsafe and then
 // immediately passing the given value to the resulting function.
 func Convert(in cty.Value, want cty.Type) (cty.Value, error) {
-	if in.Type().Equals(want.WithoutOptionalAttributesDeep()) {
-		return in, nil
-	}
-
-	conv := GetConversionUnsafe(in.Type(), want)
-	if conv == nil {
-		return cty.NilVal, errors.New(MismatchMessage(in.Type(), want))
-	}
-	return conv(in)
+	return defaultConverter.convert(in, want)
 }
 
 // Unify attempts to find the most general type that can be converted from
@@ -73,11 +63,11 @@ func Convert(in cty.Value, want cty.Type) (cty.Value, error) {
 // degenerate case of an empty slice of types, the returned type is itself
 // cty.DynamicPseudoType and no conversions are attempted.
 func Unify(types []cty.Type) (cty.Type, []Conversion) {
-	return unify(types, false)
+	return defaultConverter.unify(types, false)
 }
 
 // UnifyUnsafe is the same as Unify except that it may return unsafe
 // conversions in situations where a safe conversion isn't also available.
 func UnifyUnsafe(types []cty.Type) (cty.Type, []Conversion) {
-	return unify(types, true)
+	return defaultConverter.unify(types, true)
 }
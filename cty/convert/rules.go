@@ -0,0 +1,161 @@
+package convert
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Rule is a custom conversion rule, which can teach a Converter about
+// conversions that this package doesn't otherwise support, or replace the
+// built-in conversion between a particular pair of types.
+//
+// Each field is a reference to a function that can either be nil or can be
+// set to an implementation of the corresponding part of the rule.
+type Rule struct {
+	// GetConversion returns a conversion from type in to type out if the
+	// rule supports one, or nil if it doesn't, along with whether the
+	// conversion is safe. A safe conversion must succeed for any value of
+	// type in, while an unsafe conversion may return an error for some
+	// values.
+	//
+	// The conversion is called only with known, non-null, unmarked values
+	// of type in, because the Converter deals with the other values itself
+	// in the same way as for the built-in conversions. The conversion must
+	// return a value of type out, without any optional attribute
+	// annotations.
+	GetConversion func(in, out cty.Type) (conv Conversion, safe bool)
+
+	// MismatchMessage optionally returns a message describing why values of
+	// type got cannot be converted to type want, for use when neither this
+	// rule nor any other can convert between the two types. Return an empty
+	// string to leave the message to any later rule or to the built-in
+	// rules.
+	MismatchMessage func(got, want cty.Type) string
+}
+
+// Converter performs conversions using a set of custom rules in addition to
+// the built-in rules of this package. Its methods are like the package-level
+// functions of the same names, except that they consider the custom rules.
+//
+// The custom rules take priority over the built-in rules for any pair of
+// types that they support, and are consulted in the order they were given
+// to NewConverter so that the first rule that supports a pair of types is
+// the one that's used. A Converter consults its rules for each pair of
+// types it visits, including the attributes and elements nested inside
+// structural and collection types, with the following exceptions where the
+// built-in rules always apply:
+//
+//   - There is no conversion between a type and itself.
+//   - Any type can be converted to cty.DynamicPseudoType without changing it.
+//   - cty.DynamicPseudoType has an unsafe conversion to any other type.
+//
+// A rule that offers only an unsafe conversion is ignored when looking for a
+// safe conversion, and so then the later rules and the built-in rules are
+// consulted instead.
+//
+// A Converter is immutable once created, and so it's safe to use the same
+// Converter concurrently.
+type Converter struct {
+	c converter
+}
+
+// NewConverter returns a Converter that uses the given options and rules.
+func NewConverter(opts Options, rules ...Rule) *Converter {
+	return &Converter{
+		c: converter{
+			opts:  opts,
+			rules: append([]Rule(nil), rules...),
+		},
+	}
+}
+
+// GetConversion returns a Conversion between the given in and out Types if
+// a safe one is available, or returns nil otherwise.
+func (conv *Converter) GetConversion(in cty.Type, out cty.Type) Conversion {
+	return retConversion(conv.c.getConversion(in, out, false))
+}
+
+// GetConversionUnsafe returns a Conversion between the given in and out
+// Types if either a safe or unsafe one is available, or returns nil
+// otherwise.
+func (conv *Converter) GetConversionUnsafe(in cty.Type, out cty.Type) Conversion {
+	return retConversion(conv.c.getConversion(in, out, true))
+}
+
+// Convert returns the result of converting the given value to the given
+// type if a safe or unsafe conversion is available, or returns an error if
+// such a conversion is impossible.
+func (conv *Converter) Convert(in cty.Value, want cty.Type) (cty.Value, error) {
+	return conv.c.convert(in, want)
+}
+
+// Unify attempts to find the most general type that can be converted from
+// all of the given types, as with the package-level Unify function.
+func (conv *Converter) Unify(types []cty.Type) (cty.Type, []Conversion) {
+	return conv.c.unify(types, false)
+}
+
+// UnifyUnsafe is the same as Unify except that it may return unsafe
+// conversions in situations where a safe conversion isn't also available.
+func (conv *Converter) UnifyUnsafe(types []cty.Type) (cty.Type, []Conversion) {
+	return conv.c.unify(types, true)
+}
+
+// MismatchMessage returns an English-language description of the
+// differences between got and want, phrased as a reason why got does not
+// conform to want, as with the package-level MismatchMessage function.
+func (conv *Converter) MismatchMessage(got, want cty.Type) string {
+	return conv.c.mismatchMessage(got, want)
+}
+
+// CheckAssignability classifies whether values of type "in" can be used
+// where type "out" is expected, as with the package-level CheckAssignability
+// function.
+func (conv *Converter) CheckAssignability(in, out cty.Type) Assignability {
+	return conv.c.checkAssignability(in, out)
+}
+
+// NewCache returns a Cache that holds at most the given number of
+// conversions, and that returns the same conversions as the receiver.
+func (conv *Converter) NewCache(size int) *Cache {
+	return newCache(&conv.c, size)
+}
+
+// ruleConversion returns the conversion offered by the first of the
+// converter's rules that supports the given types, or nil if none do.
+func (c *converter) ruleConversion(in, out cty.Type, unsafe bool) conversion {
+	if len(c.rules) == 0 || in.Equals(out) {
+		return nil
+	}
+	for _, rule := range c.rules {
+		if rule.GetConversion == nil {
+			continue
+		}
+		conv, safe := rule.GetConversion(in, out)
+		if conv == nil || (!safe && !unsafe) {
+			continue
+		}
+		return func(in cty.Value, path cty.Path) (cty.Value, error) {
+			v, err := conv(in)
+			if err != nil {
+				return cty.NilVal, path.NewError(err)
+			}
+			return v, nil
+		}
+	}
+	return nil
+}
+
+// ruleMismatchError returns an error with the message from the first of the
+// converter's rules that describes the mismatch between the given types, or
+// nil if none do.
+func (c *converter) ruleMismatchError(got, want cty.Type) error {
+	for _, rule := range c.rules {
+		if rule.MismatchMessage == nil {
+			continue
+		}
+		if msg := rule.MismatchMessage(got, want); msg != "" {
+			return typeMismatchError(got, want, msg)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package convert
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+var testDateType = cty.Object(map[string]cty.Type{
+	"year":  cty.Number,
+	"month": cty.Number,
+	"day":   cty.Number,
+})
+
+// testDateRule is a rule that converts RFC 3339 timestamp strings to
+// testDateType, unsafely because not all strings are valid timestamps.
+var testDateRule = Rule{
+	GetConversion: func(in, out cty.Type) (Conversion, bool) {
+		if !in.Equals(cty.String) || !out.Equals(testDateType) {
+			return nil, false
+		}
+		return func(in cty.Value) (cty.Value, error) {
+			t, err := time.Parse(time.RFC3339, in.AsString())
+			if err != nil {
+				return cty.NilVal, fmt.Errorf("invalid timestamp: %s", in.AsString())
+			}
+			return cty.ObjectVal(map[string]cty.Value{
+				"year":  cty.NumberIntVal(int64(t.Year())),
+				"month": cty.NumberIntVal(int64(t.Month())),
+				"day":   cty.NumberIntVal(int64(t.Day())),
+			}), nil
+		}, false
+	},
+	MismatchMessage: func(got, want cty.Type) string {
+		if !want.Equals(testDateType) {
+			return ""
+		}
+		return "an RFC 3339 timestamp string is required"
+	},
+}
+
+// testYesNoRule is a rule that replaces the built-in conversion from bool to
+// string, safely.
+var testYesNoRule = Rule{
+	GetConversion: func(in, out cty.Type) (Conversion, bool) {
+		if in != cty.Bool || out != cty.String {
+			return nil, false
+		}
+		return func(in cty.Value) (cty.Value, error) {
+			if in.True() {
+				return cty.StringVal("yes"), nil
+			}
+			return cty.StringVal("no"), nil
+		}, true
+	},
+}
+
+func TestConverterConvert(t *testing.T) {
+	conv := NewConverter(Options{}, testDateRule, testYesNoRule)
+
+	tests := []struct {
+		In      cty.Value
+		Want    cty.Type
+		Result  cty.Value
+		WantErr string
+	}{
+		{
+			In:     cty.StringVal("2024-02-29T12:00:00Z"),
+			Want:   testDateType,
+			Result: cty.ObjectVal(map[string]cty.Value{"year": cty.NumberIntVal(2024), "month": cty.NumberIntVal(2), "day": cty.NumberIntVal(29)}),
+		},
+		{
+			In:     cty.True,
+			Want:   cty.String,
+			Result: cty.StringVal("yes"),
+		},
+		{
+			In:     cty.NumberIntVal(1),
+			Want:   cty.String,
+			Result: cty.StringVal("1"),
+		},
+		{
+			In: cty.ObjectVal(map[string]cty.Value{
+				"when":  cty.StringVal("2024-02-29T12:00:00Z"),
+				"flags": cty.TupleVal([]cty.Value{cty.False, cty.StringVal("maybe")}),
+			}),
+			Want: cty.Object(map[string]cty.Type{
+				"when":  testDateType,
+				"flags": cty.List(cty.String),
+			}),
+			Result: cty.ObjectVal(map[string]cty.Value{
+				"when":  cty.ObjectVal(map[string]cty.Value{"year": cty.NumberIntVal(2024), "month": cty.NumberIntVal(2), "day": cty.NumberIntVal(29)}),
+				"flags": cty.ListVal([]cty.Value{cty.StringVal("no"), cty.StringVal("maybe")}),
+			}),
+		},
+		{
+			In:     cty.UnknownVal(cty.String),
+			Want:   testDateType,
+			Result: cty.UnknownVal(testDateType),
+		},
+		{
+			In:     cty.NullVal(cty.String),
+			Want:   testDateType,
+			Result: cty.NullVal(testDateType),
+		},
+		{
+			In:     cty.True.Mark("sensitive"),
+			Want:   cty.String,
+			Result: cty.StringVal("yes").Mark("sensitive"),
+		},
+		{
+			In:      cty.ListVal([]cty.Value{cty.StringVal("2024-02-30")}),
+			Want:    cty.List(testDateType),
+			WantErr: "[cty.NumberIntVal(0)]: invalid timestamp: 2024-02-30",
+		},
+		{
+			In:      cty.NumberIntVal(2024),
+			Want:    testDateType,
+			WantErr: "an RFC 3339 timestamp string is required",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v to %#v", test.In, test.Want), func(t *testing.T) {
+			got, err := conv.Convert(test.In, test.Want)
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v", got)
+				}
+				if got := errorStrForTesting(err); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.Result) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Result)
+			}
+		})
+	}
+}
+
+func TestConverterGetConversion(t *testing.T) {
+	conv := NewConverter(Options{}, testDateRule)
+
+	if got := conv.GetConversion(cty.String, testDateType); got != nil {
+		t.Errorf("unexpected safe conversion from an unsafe rule")
+	}
+	if got := conv.GetConversionUnsafe(cty.String, testDateType); got == nil {
+		t.Errorf("no unsafe conversion")
+	}
+
+	// The rules don't affect the package-level functions.
+	if got := GetConversionUnsafe(cty.String, testDateType); got != nil {
+		t.Errorf("unexpected unsafe conversion without the rule")
+	}
+
+	// A rule that only offers an unsafe conversion doesn't prevent a later
+	// rule from offering a safe one.
+	safeDateRule := Rule{
+		GetConversion: func(in, out cty.Type) (Conversion, bool) {
+			if !in.Equals(cty.String) || !out.Equals(testDateType) {
+				return nil, false
+			}
+			return func(in cty.Value) (cty.Value, error) {
+				return cty.ObjectVal(map[string]cty.Value{
+					"year":  cty.Zero,
+					"month": cty.Zero,
+					"day":   cty.Zero,
+				}), nil
+			}, true
+		},
+	}
+	conv = NewConverter(Options{}, testDateRule, safeDateRule)
+	if got := conv.GetConversion(cty.String, testDateType); got == nil {
+		t.Errorf("no safe conversion from the later rule")
+	}
+}
+
+func TestConverterOptions(t *testing.T) {
+	// Rules take priority even over conversions the options disable.
+	conv := NewConverter(Strict, testYesNoRule)
+	got, err := conv.Convert(cty.False, cty.String)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := cty.StringVal("no"); !got.RawEquals(want) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	_, err = conv.Convert(cty.NumberIntVal(1), cty.String)
+	if err == nil {
+		t.Fatalf("no error for disabled conversion")
+	}
+}
+
+func TestConverterUnify(t *testing.T) {
+	conv := NewConverter(Options{}, testDateRule)
+	types := []cty.Type{cty.String, testDateType}
+
+	if ty, _ := Unify(types); ty != cty.NilType {
+		t.Fatalf("unexpected unification without the rule: %#v", ty)
+	}
+	if ty, _ := conv.Unify(types); ty != cty.NilType {
+		t.Fatalf("unexpected safe unification with an unsafe rule: %#v", ty)
+	}
+
+	ty, convs := conv.UnifyUnsafe(types)
+	if !ty.Equals(testDateType) {
+		t.Fatalf("wrong type\ngot:  %#v\nwant: %#v", ty, testDateType)
+	}
+	if convs[0] == nil || convs[1] != nil {
+		t.Fatalf("wrong conversions %#v", convs)
+	}
+	got, err := convs[0](cty.StringVal("2000-01-02T00:00:00Z"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := cty.ObjectVal(map[string]cty.Value{"year": cty.NumberIntVal(2000), "month": cty.NumberIntVal(1), "day": cty.NumberIntVal(2)})
+	if !got.RawEquals(want) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestConverterMismatchMessage(t *testing.T) {
+	conv := NewConverter(Options{}, testDateRule)
+
+	got := conv.MismatchMessage(
+		cty.Object(map[string]cty.Type{"when": cty.Bool}),
+		cty.Object(map[string]cty.Type{"when": testDateType}),
+	)
+	want := `attribute "when": an RFC 3339 timestamp string is required`
+	if got != want {
+		t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+	}
+
+	// When the rule has nothing to say, the built-in messages apply.
+	got = conv.MismatchMessage(cty.Bool, cty.Number)
+	want = "number required, but have bool"
+	if got != want {
+		t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestConverterCache(t *testing.T) {
+	cache := NewConverter(Options{}, testYesNoRule).NewCache(4)
+	got, err := cache.Convert(cty.True, cty.String)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := cty.StringVal("yes"); !got.RawEquals(want) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
@@ -0,0 +1,229 @@
+package convert
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// AttributeSuggestion describes an attribute of a given object type that is
+// probably a misspelling of an attribute that a wanted object type expects,
+// because the given type doesn't have the wanted attribute and the two names
+// are similar.
+type AttributeSuggestion struct {
+	// Path is the path to the object type that the attributes belong to,
+	// using the same conventions as cty.WalkType: collection elements are
+	// represented by an IndexStep with an unknown key and tuple elements
+	// are represented by an IndexStep with a known number key.
+	Path cty.Path
+
+	// Got is the name of the attribute in the given type that the wanted
+	// type does not expect.
+	Got string
+
+	// Want is the name of the attribute in the wanted type that is missing
+	// from the given type, and that Got is probably a misspelling of.
+	Want string
+
+	// Optional is true if Want is an optional attribute, in which case a
+	// conversion can succeed even though the attribute is misspelled but
+	// the given value for it will be silently discarded.
+	Optional bool
+}
+
+// SuggestAttributes searches the given types for object attributes that
+// appear in got but not in want and whose names are similar to attributes
+// that appear in want but not in got, which are likely to be misspellings.
+//
+// The search visits all of the object types nested inside the given types
+// whose paths correspond, including the elements of collection and tuple
+// types. Each unexpected attribute is suggested for at most one wanted
+// attribute, and vice-versa. The result is sorted by path and then by the
+// wanted attribute name, and is empty if there are no likely misspellings.
+//
+// MismatchMessage includes the suggestions for required attributes in its
+// messages, so this function is needed only by callers that want to present
+// suggestions in some other way, or to detect misspellings of optional
+// attributes, which don't cause conversion to fail.
+func SuggestAttributes(got, want cty.Type) []AttributeSuggestion {
+	return suggestAttributes(nil, got, want, nil)
+}
+
+func suggestAttributes(path cty.Path, got, want cty.Type, ret []AttributeSuggestion) []AttributeSuggestion {
+	switch {
+	case got.IsObjectType() && want.IsObjectType():
+		ret = append(ret, attributeSuggestions(path, got, want)...)
+		gotAtys := got.AttributeTypes()
+		wantAtys := want.AttributeTypes()
+		names := make([]string, 0, len(wantAtys))
+		for name := range wantAtys {
+			if _, exists := gotAtys[name]; exists {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			path := append(path, cty.GetAttrStep{Name: name})
+			ret = suggestAttributes(path, gotAtys[name], wantAtys[name], ret)
+		}
+
+	case got.IsObjectType() && want.IsMapType():
+		atys := got.AttributeTypes()
+		names := make([]string, 0, len(atys))
+		for name := range atys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			path := append(path, cty.IndexStep{Key: cty.StringVal(name)})
+			ret = suggestAttributes(path, atys[name], want.ElementType(), ret)
+		}
+
+	case got.IsTupleType() && (want.IsTupleType() || want.IsListType() || want.IsSetType()):
+		gotEtys := got.TupleElementTypes()
+		for i, gotEty := range gotEtys {
+			var wantEty cty.Type
+			if want.IsTupleType() {
+				wantEtys := want.TupleElementTypes()
+				if i >= len(wantEtys) {
+					break
+				}
+				wantEty = wantEtys[i]
+			} else {
+				wantEty = want.ElementType()
+			}
+			path := append(path, cty.IndexStep{Key: cty.NumberIntVal(int64(i))})
+			ret = suggestAttributes(path, gotEty, wantEty, ret)
+		}
+
+	case got.IsCollectionType() && want.IsCollectionType():
+		wantEty := want.ElementType()
+		var keyTy cty.Type
+		switch {
+		case want.IsListType():
+			keyTy = cty.Number
+		case want.IsMapType():
+			keyTy = cty.String
+		default:
+			keyTy = wantEty
+		}
+		path := append(path, cty.IndexStep{Key: cty.UnknownVal(keyTy)})
+		ret = suggestAttributes(path, got.ElementType(), wantEty, ret)
+	}
+	return ret
+}
+
+// attributeSuggestions returns suggestions for the attributes of the given
+// object types only, without visiting any nested types.
+func attributeSuggestions(path cty.Path, got, want cty.Type) []AttributeSuggestion {
+	gotAtys := got.AttributeTypes()
+	wantAtys := want.AttributeTypes()
+
+	type candidate struct {
+		got, want string
+		dist      int
+	}
+	var candidates []candidate
+	for wantName := range wantAtys {
+		if _, exists := gotAtys[wantName]; exists {
+			continue
+		}
+		// Short names are too easily within a small edit distance of each
+		// other by coincidence, so we allow more edits for longer names.
+		limit := min(3, (len([]rune(wantName))-1)/3)
+		for gotName := range gotAtys {
+			if _, expected := wantAtys[gotName]; expected {
+				continue
+			}
+			if dist := editDistance(gotName, wantName); dist <= limit {
+				candidates = append(candidates, candidate{gotName, wantName, dist})
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// We'll pair up the closest names first, so that when one unexpected
+	// attribute is similar to several missing attributes (or vice-versa)
+	// we'll suggest the most likely one.
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		switch {
+		case a.dist != b.dist:
+			return a.dist < b.dist
+		case a.want != b.want:
+			return a.want < b.want
+		default:
+			return a.got < b.got
+		}
+	})
+	usedGot := make(map[string]bool)
+	usedWant := make(map[string]bool)
+	var ret []AttributeSuggestion
+	for _, c := range candidates {
+		if usedGot[c.got] || usedWant[c.want] {
+			continue
+		}
+		usedGot[c.got] = true
+		usedWant[c.want] = true
+		ret = append(ret, AttributeSuggestion{
+			Path:     path.Copy(),
+			Got:      c.got,
+			Want:     c.want,
+			Optional: want.AttributeOptional(c.want),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Want < ret[j].Want
+	})
+	return ret
+}
+
+// suggestionsMessage returns a phrase suggesting the corrections described
+// by the given suggestions, for appending to a message that reports missing
+// attributes, or an empty string if there are no suggestions.
+func suggestionsMessage(suggestions []AttributeSuggestion) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	buf.WriteString("; did you mean ")
+	for i, s := range suggestions {
+		switch {
+		case i == 0:
+		case i == len(suggestions)-1:
+			buf.WriteString(" and ")
+		default:
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "%q instead of %q", s.Want, s.Got)
+	}
+	buf.WriteString("?")
+	return buf.String()
+}
+
+// editDistance returns the Levenshtein distance between the given strings,
+// counting each inserted, deleted, or substituted character as one edit.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(br)]
+}
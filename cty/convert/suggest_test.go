@@ -0,0 +1,151 @@
+package convert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSuggestAttributes(t *testing.T) {
+	tests := []struct {
+		Got, Want cty.Type
+		Expected  []AttributeSuggestion
+	}{
+		{
+			cty.EmptyObject,
+			cty.Object(map[string]cty.Type{"name": cty.String}),
+			nil,
+		},
+		{
+			cty.Object(map[string]cty.Type{"name": cty.String}),
+			cty.Object(map[string]cty.Type{"name": cty.String}),
+			nil,
+		},
+		{
+			cty.Object(map[string]cty.Type{"nme": cty.String}),
+			cty.Object(map[string]cty.Type{"name": cty.String}),
+			[]AttributeSuggestion{
+				{Path: nil, Got: "nme", Want: "name"},
+			},
+		},
+		{
+			// Names that are too different are not suggested.
+			cty.Object(map[string]cty.Type{"id": cty.String}),
+			cty.Object(map[string]cty.Type{"name": cty.String}),
+			nil,
+		},
+		{
+			// Each unexpected attribute is suggested only once, for the
+			// closest missing attribute.
+			cty.Object(map[string]cty.Type{"colour": cty.String}),
+			cty.Object(map[string]cty.Type{
+				"color":  cty.String,
+				"colors": cty.String,
+			}),
+			[]AttributeSuggestion{
+				{Path: nil, Got: "colour", Want: "color"},
+			},
+		},
+		{
+			cty.Object(map[string]cty.Type{"nme": cty.String}),
+			cty.ObjectWithOptionalAttrs(map[string]cty.Type{"name": cty.String}, []string{"name"}),
+			[]AttributeSuggestion{
+				{Path: nil, Got: "nme", Want: "name", Optional: true},
+			},
+		},
+		{
+			cty.Object(map[string]cty.Type{
+				"nme": cty.String,
+				"network": cty.Object(map[string]cty.Type{
+					"subnets": cty.List(cty.Object(map[string]cty.Type{
+						"cidr_blok": cty.String,
+					})),
+				}),
+			}),
+			cty.Object(map[string]cty.Type{
+				"name": cty.String,
+				"network": cty.Object(map[string]cty.Type{
+					"subnets": cty.Set(cty.Object(map[string]cty.Type{
+						"cidr_block": cty.String,
+					})),
+				}),
+			}),
+			[]AttributeSuggestion{
+				{Path: nil, Got: "nme", Want: "name"},
+				{
+					Path: cty.GetAttrPath("network").GetAttr("subnets").Index(cty.UnknownVal(cty.Object(map[string]cty.Type{
+						"cidr_block": cty.String,
+					}))),
+					Got:  "cidr_blok",
+					Want: "cidr_block",
+				},
+			},
+		},
+		{
+			cty.Tuple([]cty.Type{
+				cty.EmptyObject,
+				cty.Object(map[string]cty.Type{"nme": cty.String}),
+			}),
+			cty.List(cty.Object(map[string]cty.Type{"name": cty.String})),
+			[]AttributeSuggestion{
+				{Path: cty.IndexPath(cty.NumberIntVal(1)), Got: "nme", Want: "name"},
+			},
+		},
+		{
+			cty.Object(map[string]cty.Type{
+				"a": cty.Object(map[string]cty.Type{"nme": cty.String}),
+			}),
+			cty.Map(cty.Object(map[string]cty.Type{"name": cty.String})),
+			[]AttributeSuggestion{
+				{Path: cty.IndexPath(cty.StringVal("a")), Got: "nme", Want: "name"},
+			},
+		},
+		{
+			cty.Map(cty.Object(map[string]cty.Type{"nme": cty.String})),
+			cty.Map(cty.Object(map[string]cty.Type{"name": cty.String})),
+			[]AttributeSuggestion{
+				{Path: cty.IndexPath(cty.UnknownVal(cty.String)), Got: "nme", Want: "name"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v to %#v", test.Got, test.Want), func(t *testing.T) {
+			got := SuggestAttributes(test.Got, test.Want)
+			if len(got) != len(test.Expected) {
+				t.Fatalf("wrong number of suggestions\ngot:  %#v\nwant: %#v", got, test.Expected)
+			}
+			for i := range got {
+				g, w := got[i], test.Expected[i]
+				if g.Got != w.Got || g.Want != w.Want || g.Optional != w.Optional || !g.Path.Equals(w.Path) {
+					t.Errorf("wrong suggestion %d\ngot:  %#v\nwant: %#v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		A, B string
+		Want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"name", "name", 0},
+		{"nme", "name", 1},
+		{"instnce_type", "instance_type", 1},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%q %q", test.A, test.B), func(t *testing.T) {
+			if got := editDistance(test.A, test.B); got != test.Want {
+				t.Errorf("wrong result %d; want %d", got, test.Want)
+			}
+		})
+	}
+}
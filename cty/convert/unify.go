@@ -1,6 +1,10 @@
 package convert
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -15,7 +19,17 @@ import (
 // structure under the given types several times, especially when given a
 // list of types for which unification is not possible, since each permutation
 // will be tried to determine that result.
-func unify(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
+func (c *converter) unify(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
+	return c.unifyExplained(types, unsafe, nil)
+}
+
+// unifyExplained is the main implementation of unify, which also records its
+// decisions in the given explainer if it is not nil.
+//
+// Plain unification passes a nil explainer, so the arguments for explainer
+// methods, such as index slices and path steps, are built only when ex is
+// not nil to avoid slowing it down.
+func (c *converter) unifyExplained(types []cty.Type, unsafe bool, ex *unifyExplainer) (cty.Type, []Conversion) {
 	if len(types) == 0 {
 		// Degenerate case
 		return cty.NilType, nil
@@ -54,37 +68,46 @@ func unify(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
 		}
 		switch {
 		case mapCt > 0 && (mapCt+dynamicCt) == len(types):
-			return unifyCollectionTypes(cty.Map, types, unsafe, dynamicCt > 0)
+			return c.unifyCollectionTypes(cty.Map, types, unsafe, dynamicCt > 0, ex)
 
 		case mapCt > 0 && (mapCt+objectCt+dynamicCt) == len(types):
 			// Objects often contain map data, but are not directly typed as
 			// such due to language constructs or function types. Try to unify
 			// them as maps first before falling back to heterogeneous type
 			// conversion.
-			ty, convs := unifyObjectsAsMaps(types, unsafe)
+			ty, convs := c.unifyObjectsAsMaps(types, unsafe, ex)
 			// If we got a map back, we know the unification was successful.
 			if ty.IsMapType() {
 				return ty, convs
 			}
 		case listCt > 0 && (listCt+dynamicCt) == len(types):
-			return unifyCollectionTypes(cty.List, types, unsafe, dynamicCt > 0)
+			return c.unifyCollectionTypes(cty.List, types, unsafe, dynamicCt > 0, ex)
 		case listCt > 0 && (listCt+tupleCt+dynamicCt) == len(types):
 			// Tuples are often lists in disguise, and we may be able to
 			// unify them as such.
-			ty, convs := unifyTuplesAsList(types, unsafe)
+			ty, convs := c.unifyTuplesAsList(types, unsafe, ex)
 			// if we got a list back, we know the unification was successful.
 			// Otherwise we will fall back to the heterogeneous type codepath.
 			if ty.IsListType() {
 				return ty, convs
 			}
 		case setCt > 0 && (setCt+dynamicCt) == len(types):
-			return unifyCollectionTypes(cty.Set, types, unsafe, dynamicCt > 0)
+			return c.unifyCollectionTypes(cty.Set, types, unsafe, dynamicCt > 0, ex)
 		case objectCt > 0 && (objectCt+dynamicCt) == len(types):
-			return unifyObjectTypes(types, unsafe, dynamicCt > 0)
+			return c.unifyObjectTypes(types, unsafe, dynamicCt > 0, ex)
 		case tupleCt > 0 && (tupleCt+dynamicCt) == len(types):
-			return unifyTupleTypes(types, unsafe, dynamicCt > 0)
+			return c.unifyTupleTypes(types, unsafe, dynamicCt > 0, ex)
 		case objectCt > 0 && tupleCt > 0:
 			// Can never unify object and tuple types since they have incompatible kinds
+			if ex != nil {
+				var idxs []int
+				for i, ty := range types {
+					if ty.IsObjectType() || ty.IsTupleType() {
+						idxs = append(idxs, i)
+					}
+				}
+				ex.record(UnifyKindConflict, idxs, "object and tuple types cannot be unified")
+			}
 			return cty.NilType, nil
 		}
 	}
@@ -95,6 +118,7 @@ func unify(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
 	// our result type. We'll now walk through these and choose the first
 	// one we encounter for which conversions exist for all source types.
 	conversions := make([]Conversion, len(types))
+	var rejected []int // for each rejected preference, the type that couldn't convert to it
 Preferences:
 	for _, wantTypeIdx := range prefOrder {
 		wantType := types[wantTypeIdx]
@@ -110,29 +134,41 @@ Preferences:
 				continue
 			}
 
-			if unsafe {
-				conversions[i] = GetConversionUnsafe(tryType, wantType)
-			} else {
-				conversions[i] = GetConversion(tryType, wantType)
-			}
+			conversions[i] = retConversion(c.getConversion(tryType, wantType, unsafe))
 
 			if conversions[i] == nil {
 				// wantType is not a suitable unification type, so we'll
 				// try the next one in our preference order.
+				if ex != nil {
+					rejected = append(rejected, wantTypeIdx, i)
+				}
 				continue Preferences
 			}
 		}
 
+		if ex != nil && wantType == cty.DynamicPseudoType {
+			var idxs []int
+			for i, ty := range types {
+				if ty == cty.DynamicPseudoType {
+					idxs = append(idxs, i)
+				}
+			}
+			ex.record(UnifyDynamic, idxs, "the result must be dynamic because some of the types are not yet known")
+		}
 		return wantType, conversions
 	}
 
 	// If we fall out here, no unification is possible
+	for i := 0; i < len(rejected); i += 2 {
+		wantType, tryType := types[rejected[i]], types[rejected[i+1]]
+		ex.record(UnifyNoConversion, []int{rejected[i+1], rejected[i]}, "cannot use %s, because there is no conversion to it from %s", wantType.FriendlyName(), tryType.FriendlyName())
+	}
 	return cty.NilType, nil
 }
 
 // unifyTuplesAsList attempts to first see if the tuples unify as lists, then
 // re-unifies the given types with the list in place of the tuples.
-func unifyTuplesAsList(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
+func (c *converter) unifyTuplesAsList(types []cty.Type, unsafe bool, ex *unifyExplainer) (cty.Type, []Conversion) {
 	var tuples []cty.Type
 	var tupleIdxs []int
 	for i, t := range types {
@@ -142,7 +178,7 @@ func unifyTuplesAsList(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
 		}
 	}
 
-	ty, tupleConvs := unifyTupleTypesToList(tuples, unsafe)
+	ty, tupleConvs := c.unifyTupleTypesToList(tuples, unsafe, ex.subset(tupleIdxs))
 	if !ty.IsListType() {
 		return cty.NilType, nil
 	}
@@ -157,7 +193,7 @@ func unifyTuplesAsList(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
 		listed[idx] = ty
 	}
 
-	newTy, convs := unify(listed, unsafe)
+	newTy, convs := c.unifyExplained(listed, unsafe, ex)
 	if !newTy.IsListType() {
 		return cty.NilType, nil
 	}
@@ -189,7 +225,7 @@ func unifyTuplesAsList(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
 
 // unifyObjectsAsMaps attempts to first see if the objects unify as maps, then
 // re-unifies the given types with the map in place of the objects.
-func unifyObjectsAsMaps(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
+func (c *converter) unifyObjectsAsMaps(types []cty.Type, unsafe bool, ex *unifyExplainer) (cty.Type, []Conversion) {
 	var objs []cty.Type
 	var objIdxs []int
 	for i, t := range types {
@@ -199,7 +235,7 @@ func unifyObjectsAsMaps(types []cty.Type, unsafe bool) (cty.Type, []Conversion)
 		}
 	}
 
-	ty, objConvs := unifyObjectTypesToMap(objs, unsafe)
+	ty, objConvs := c.unifyObjectTypesToMap(objs, unsafe, ex.subset(objIdxs))
 	if !ty.IsMapType() {
 		return cty.NilType, nil
 	}
@@ -214,7 +250,7 @@ func unifyObjectsAsMaps(types []cty.Type, unsafe bool) (cty.Type, []Conversion)
 		mapped[idx] = ty
 	}
 
-	newTy, convs := unify(mapped, unsafe)
+	newTy, convs := c.unifyExplained(mapped, unsafe, ex)
 	if !newTy.IsMapType() {
 		return cty.NilType, nil
 	}
@@ -244,19 +280,23 @@ func unifyObjectsAsMaps(types []cty.Type, unsafe bool) (cty.Type, []Conversion)
 	return newTy, convs
 }
 
-func unifyCollectionTypes(collectionType func(cty.Type) cty.Type, types []cty.Type, unsafe bool, hasDynamic bool) (cty.Type, []Conversion) {
+func (c *converter) unifyCollectionTypes(collectionType func(cty.Type) cty.Type, types []cty.Type, unsafe bool, hasDynamic bool, ex *unifyExplainer) (cty.Type, []Conversion) {
 	// If we had any dynamic types in the input here then we can't predict
 	// what path we'll take through here once these become known types, so
 	// we'll conservatively produce DynamicVal for these.
 	if hasDynamic {
-		return unifyAllAsDynamic(types)
+		return unifyAllAsDynamic(types, ex)
 	}
 
 	elemTypes := make([]cty.Type, 0, len(types))
 	for _, ty := range types {
 		elemTypes = append(elemTypes, ty.ElementType())
 	}
-	retElemType, _ := unify(elemTypes, unsafe)
+	var elemEx *unifyExplainer
+	if ex != nil {
+		elemEx = ex.nested(cty.CollectionElementStep(types[0]), allIndices(types))
+	}
+	retElemType, _ := c.unifyExplained(elemTypes, unsafe, elemEx)
 	if retElemType == cty.NilType {
 		return cty.NilType, nil
 	}
@@ -268,11 +308,7 @@ func unifyCollectionTypes(collectionType func(cty.Type) cty.Type, types []cty.Ty
 		if ty.Equals(retTy) {
 			continue
 		}
-		if unsafe {
-			conversions[i] = GetConversionUnsafe(ty, retTy)
-		} else {
-			conversions[i] = GetConversion(ty, retTy)
-		}
+		conversions[i] = retConversion(c.getConversion(ty, retTy, unsafe))
 		if conversions[i] == nil {
 			// Shouldn't be reachable, since we were able to unify
 			return cty.NilType, nil
@@ -282,12 +318,12 @@ func unifyCollectionTypes(collectionType func(cty.Type) cty.Type, types []cty.Ty
 	return retTy, conversions
 }
 
-func unifyObjectTypes(types []cty.Type, unsafe bool, hasDynamic bool) (cty.Type, []Conversion) {
+func (c *converter) unifyObjectTypes(types []cty.Type, unsafe bool, hasDynamic bool, ex *unifyExplainer) (cty.Type, []Conversion) {
 	// If we had any dynamic types in the input here then we can't predict
 	// what path we'll take through here once these become known types, so
 	// we'll conservatively produce DynamicVal for these.
 	if hasDynamic {
-		return unifyAllAsDynamic(types)
+		return unifyAllAsDynamic(types, ex)
 	}
 
 	// There are two different ways we can succeed here:
@@ -311,13 +347,15 @@ func unifyObjectTypes(types []cty.Type, unsafe bool, hasDynamic bool) (cty.Type,
 		if len(thisAttrs) != len(firstAttrs) {
 			// If number of attributes is different then there can be no
 			// object type in common.
-			return unifyObjectTypesToMap(types, unsafe)
+			explainObjectAttributesDiffer(types, ex)
+			return c.unifyObjectTypesToMap(types, unsafe, ex)
 		}
 		for name := range thisAttrs {
 			if _, ok := firstAttrs[name]; !ok {
 				// If attribute names don't exactly match then there can be
 				// no object type in common.
-				return unifyObjectTypesToMap(types, unsafe)
+				explainObjectAttributesDiffer(types, ex)
+				return c.unifyObjectTypesToMap(types, unsafe, ex)
 			}
 		}
 	}
@@ -327,14 +365,25 @@ func unifyObjectTypes(types []cty.Type, unsafe bool, hasDynamic bool) (cty.Type,
 	// differ.
 	retAtys := make(map[string]cty.Type)
 	atysAcross := make([]cty.Type, len(types))
-	for name := range firstAttrs {
+	var allIdxs []int
+	if ex != nil {
+		allIdxs = allIndices(types)
+	}
+	for _, name := range sortedAttributeNames(firstAttrs) {
 		for i, ty := range types {
 			atysAcross[i] = ty.AttributeType(name)
 		}
-		retAtys[name], _ = unify(atysAcross, unsafe)
+		var attrEx *unifyExplainer
+		if ex != nil {
+			attrEx = ex.nested(cty.GetAttrStep{Name: name}, allIdxs)
+		}
+		retAtys[name], _ = c.unifyExplained(atysAcross, unsafe, attrEx)
 		if retAtys[name] == cty.NilType {
 			// Cannot unify this attribute alone, which means that unification
 			// of everything down to a map type can't be possible either.
+			if ex != nil {
+				ex.record(UnifyAttributeConflict, allIdxs, "the types of attribute %q cannot be unified", name)
+			}
 			return cty.NilType, nil
 		}
 	}
@@ -345,33 +394,41 @@ func unifyObjectTypes(types []cty.Type, unsafe bool, hasDynamic bool) (cty.Type,
 		if ty.Equals(retTy) {
 			continue
 		}
-		if unsafe {
-			conversions[i] = GetConversionUnsafe(ty, retTy)
-		} else {
-			conversions[i] = GetConversion(ty, retTy)
-		}
+		conversions[i] = retConversion(c.getConversion(ty, retTy, unsafe))
 		if conversions[i] == nil {
 			// Shouldn't be reachable, since we were able to unify
-			return unifyObjectTypesToMap(types, unsafe)
+			return c.unifyObjectTypesToMap(types, unsafe, ex)
 		}
 	}
 
 	return retTy, conversions
 }
 
-func unifyObjectTypesToMap(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
+func (c *converter) unifyObjectTypesToMap(types []cty.Type, unsafe bool, ex *unifyExplainer) (cty.Type, []Conversion) {
 	// This is our fallback case for unifyObjectTypes, where we see if we can
 	// construct a map type that can accept all of the attribute types.
 
 	var atys []cty.Type
-	for _, ty := range types {
-		for _, aty := range ty.AttributeTypes() {
-			atys = append(atys, aty)
+	var atyIdxs []int
+	for i, ty := range types {
+		attrTypes := ty.AttributeTypes()
+		for _, name := range sortedAttributeNames(attrTypes) {
+			atys = append(atys, attrTypes[name])
+			if ex != nil {
+				atyIdxs = append(atyIdxs, i)
+			}
 		}
 	}
 
-	ety, _ := unify(atys, unsafe)
+	var elemEx *unifyExplainer
+	if ex != nil {
+		elemEx = ex.nested(cty.IndexStep{Key: cty.UnknownVal(cty.String)}, atyIdxs)
+	}
+	ety, _ := c.unifyExplained(atys, unsafe, elemEx)
 	if ety == cty.NilType {
+		if ex != nil {
+			ex.record(UnifyAttributeConflict, allIndices(types), "the attribute types cannot all be unified as the element type of a map")
+		}
 		return cty.NilType, nil
 	}
 
@@ -381,11 +438,7 @@ func unifyObjectTypesToMap(types []cty.Type, unsafe bool) (cty.Type, []Conversio
 		if ty.Equals(retTy) {
 			continue
 		}
-		if unsafe {
-			conversions[i] = GetConversionUnsafe(ty, retTy)
-		} else {
-			conversions[i] = GetConversion(ty, retTy)
-		}
+		conversions[i] = retConversion(c.getConversion(ty, retTy, unsafe))
 		if conversions[i] == nil {
 			return cty.NilType, nil
 		}
@@ -393,12 +446,12 @@ func unifyObjectTypesToMap(types []cty.Type, unsafe bool) (cty.Type, []Conversio
 	return retTy, conversions
 }
 
-func unifyTupleTypes(types []cty.Type, unsafe bool, hasDynamic bool) (cty.Type, []Conversion) {
+func (c *converter) unifyTupleTypes(types []cty.Type, unsafe bool, hasDynamic bool, ex *unifyExplainer) (cty.Type, []Conversion) {
 	// If we had any dynamic types in the input here then we can't predict
 	// what path we'll take through here once these become known types, so
 	// we'll conservatively produce DynamicVal for these.
 	if hasDynamic {
-		return unifyAllAsDynamic(types)
+		return unifyAllAsDynamic(types, ex)
 	}
 
 	// There are two different ways we can succeed here:
@@ -415,7 +468,10 @@ func unifyTupleTypes(types []cty.Type, unsafe bool, hasDynamic bool) (cty.Type,
 		if len(thisEtys) != len(firstEtys) {
 			// If number of elements is different then there can be no
 			// tuple type in common.
-			return unifyTupleTypesToList(types, unsafe)
+			if ex != nil {
+				ex.record(UnifyElementsDiffer, allIndices(types), "tuple types have different numbers of elements, so trying a list type instead")
+			}
+			return c.unifyTupleTypesToList(types, unsafe, ex)
 		}
 	}
 
@@ -423,14 +479,25 @@ func unifyTupleTypes(types []cty.Type, unsafe bool, hasDynamic bool) (cty.Type,
 	// have the same number of elements, though the types may differ.
 	retEtys := make([]cty.Type, len(firstEtys))
 	atysAcross := make([]cty.Type, len(types))
+	var allIdxs []int
+	if ex != nil {
+		allIdxs = allIndices(types)
+	}
 	for idx := range firstEtys {
 		for tyI, ty := range types {
 			atysAcross[tyI] = ty.TupleElementTypes()[idx]
 		}
-		retEtys[idx], _ = unify(atysAcross, unsafe)
+		var elemEx *unifyExplainer
+		if ex != nil {
+			elemEx = ex.nested(cty.IndexStep{Key: cty.NumberIntVal(int64(idx))}, allIdxs)
+		}
+		retEtys[idx], _ = c.unifyExplained(atysAcross, unsafe, elemEx)
 		if retEtys[idx] == cty.NilType {
 			// Cannot unify this element alone, which means that unification
 			// of everything down to a map type can't be possible either.
+			if ex != nil {
+				ex.record(UnifyElementConflict, allIdxs, "the types of element %d cannot be unified", idx)
+			}
 			return cty.NilType, nil
 		}
 	}
@@ -441,32 +508,39 @@ func unifyTupleTypes(types []cty.Type, unsafe bool, hasDynamic bool) (cty.Type,
 		if ty.Equals(retTy) {
 			continue
 		}
-		if unsafe {
-			conversions[i] = GetConversionUnsafe(ty, retTy)
-		} else {
-			conversions[i] = GetConversion(ty, retTy)
-		}
+		conversions[i] = retConversion(c.getConversion(ty, retTy, unsafe))
 		if conversions[i] == nil {
-			return unifyTupleTypesToList(types, unsafe)
+			return c.unifyTupleTypesToList(types, unsafe, ex)
 		}
 	}
 
 	return retTy, conversions
 }
 
-func unifyTupleTypesToList(types []cty.Type, unsafe bool) (cty.Type, []Conversion) {
+func (c *converter) unifyTupleTypesToList(types []cty.Type, unsafe bool, ex *unifyExplainer) (cty.Type, []Conversion) {
 	// This is our fallback case for unifyTupleTypes, where we see if we can
 	// construct a list type that can accept all of the element types.
 
 	var etys []cty.Type
-	for _, ty := range types {
+	var etyIdxs []int
+	for i, ty := range types {
 		for _, ety := range ty.TupleElementTypes() {
 			etys = append(etys, ety)
+			if ex != nil {
+				etyIdxs = append(etyIdxs, i)
+			}
 		}
 	}
 
-	ety, _ := unify(etys, unsafe)
+	var elemEx *unifyExplainer
+	if ex != nil {
+		elemEx = ex.nested(cty.IndexStep{Key: cty.UnknownVal(cty.Number)}, etyIdxs)
+	}
+	ety, _ := c.unifyExplained(etys, unsafe, elemEx)
 	if ety == cty.NilType {
+		if ex != nil {
+			ex.record(UnifyElementConflict, allIndices(types), "the element types cannot all be unified as the element type of a list")
+		}
 		return cty.NilType, nil
 	}
 
@@ -476,11 +550,7 @@ func unifyTupleTypesToList(types []cty.Type, unsafe bool) (cty.Type, []Conversio
 		if ty.Equals(retTy) {
 			continue
 		}
-		if unsafe {
-			conversions[i] = GetConversionUnsafe(ty, retTy)
-		} else {
-			conversions[i] = GetConversion(ty, retTy)
-		}
+		conversions[i] = retConversion(c.getConversion(ty, retTy, unsafe))
 		if conversions[i] == nil {
 			// no conversion was found
 			return cty.NilType, nil
@@ -489,7 +559,17 @@ func unifyTupleTypesToList(types []cty.Type, unsafe bool) (cty.Type, []Conversio
 	return retTy, conversions
 }
 
-func unifyAllAsDynamic(types []cty.Type) (cty.Type, []Conversion) {
+func unifyAllAsDynamic(types []cty.Type, ex *unifyExplainer) (cty.Type, []Conversion) {
+	if ex != nil {
+		var idxs []int
+		for i, ty := range types {
+			if ty == cty.DynamicPseudoType {
+				idxs = append(idxs, i)
+			}
+		}
+		ex.record(UnifyDynamic, idxs, "the result must be dynamic because some of the types are not yet known")
+	}
+
 	conversions := make([]Conversion, len(types))
 	for i := range conversions {
 		conversions[i] = func(cty.Value) (cty.Value, error) {
@@ -498,3 +578,53 @@ func unifyAllAsDynamic(types []cty.Type) (cty.Type, []Conversion) {
 	}
 	return cty.DynamicPseudoType, conversions
 }
+
+// explainObjectAttributesDiffer records in the given explainer that the given
+// object types can't be unified as an object type because they don't all
+// have the same attributes.
+func explainObjectAttributesDiffer(types []cty.Type, ex *unifyExplainer) {
+	if ex == nil {
+		return
+	}
+	counts := make(map[string]int)
+	for _, ty := range types {
+		for name := range ty.AttributeTypes() {
+			counts[name]++
+		}
+	}
+	var names []string
+	for name, count := range counts {
+		if count != len(types) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var idxs []int
+	for i, ty := range types {
+		if len(ty.AttributeTypes()) != len(counts) {
+			idxs = append(idxs, i)
+		}
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	ex.record(UnifyAttributesDiffer, idxs, "object types have different attributes (%s), so trying a map type instead", strings.Join(quoted, ", "))
+}
+
+func sortedAttributeNames(atys map[string]cty.Type) []string {
+	names := make([]string, 0, len(atys))
+	for name := range atys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func allIndices(types []cty.Type) []int {
+	ret := make([]int, len(types))
+	for i := range ret {
+		ret[i] = i
+	}
+	return ret
+}
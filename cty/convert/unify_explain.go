@@ -0,0 +1,249 @@
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// UnifyExplain is like Unify except that it also returns a report describing
+// the decisions that led to the result, which is intended to help explain
+// why unification failed or why it chose a particular type.
+func UnifyExplain(types []cty.Type) (cty.Type, []Conversion, UnifyReport) {
+	return defaultConverter.unifyExplain(types, false)
+}
+
+// UnifyUnsafeExplain is like UnifyUnsafe except that it also returns a report
+// describing the decisions that led to the result, as with UnifyExplain.
+func UnifyUnsafeExplain(types []cty.Type) (cty.Type, []Conversion, UnifyReport) {
+	return defaultConverter.unifyExplain(types, true)
+}
+
+func (c *converter) unifyExplain(types []cty.Type, unsafe bool) (cty.Type, []Conversion, UnifyReport) {
+	var report UnifyReport
+	ex := &unifyExplainer{
+		report: &report,
+		inputs: allIndices(types),
+	}
+	retTy, convs := c.unifyExplained(types, unsafe, ex)
+	if retTy == cty.NilType {
+		return retTy, convs, report
+	}
+
+	if unsafe {
+		for i, ty := range types {
+			if ty.Equals(retTy) {
+				continue
+			}
+			if c.getConversion(ty, retTy, false) == nil {
+				ex.record(UnifyUnsafeConversion, []int{i}, "the conversion from %s to %s is unsafe", ty.FriendlyName(), retTy.FriendlyName())
+			}
+		}
+	}
+	ex.record(UnifyResult, allIndices(types), "the types are unified as %s", retTy.FriendlyName())
+	return retTy, convs, report
+}
+
+// UnifyReport describes the decisions made while unifying a set of types, as
+// returned from UnifyExplain and UnifyUnsafeExplain.
+type UnifyReport struct {
+	// Steps are the decisions in the order they were made. Unification
+	// decides the types of nested elements and attributes before the types
+	// that contain them, so steps for deeper paths tend to appear before the
+	// steps for the types containing them.
+	Steps []UnifyStep
+}
+
+// UnifyStep describes a single decision made while unifying a set of types.
+type UnifyStep struct {
+	// Kind categorizes the decision, so that callers can select only the
+	// steps that are relevant to them.
+	Kind UnifyStepKind
+
+	// Path is the location within the given types that the decision relates
+	// to, using the same conventions as cty.WalkType: collection elements
+	// are represented by an IndexStep with an unknown key and tuple elements
+	// are represented by an IndexStep with a known number key. The elements
+	// of a map type unified from object types use an unknown string key.
+	Path cty.Path
+
+	// Inputs are the indices of the given types that the decision relates
+	// to, in increasing order.
+	Inputs []int
+
+	// Message describes the decision in English.
+	Message string
+}
+
+// UnifyStepKind categorizes the steps in a UnifyReport.
+type UnifyStepKind int
+
+const (
+	// UnifyResult describes the type chosen for the whole set of types. It
+	// is always the last step in the report of a successful unification.
+	UnifyResult UnifyStepKind = iota
+
+	// UnifyDynamic reports that the result must be cty.DynamicPseudoType
+	// because some of the types are not yet known. Inputs are the types that
+	// are cty.DynamicPseudoType.
+	UnifyDynamic
+
+	// UnifyKindConflict reports that the types include both object and tuple
+	// types, which can never be unified.
+	UnifyKindConflict
+
+	// UnifyAttributesDiffer reports that a set of object types don't all
+	// have the same attributes, so they can't be unified as an object type
+	// and so unification tries a map type instead. Inputs are the types that
+	// don't have all of the attributes.
+	UnifyAttributesDiffer
+
+	// UnifyAttributeConflict reports that the types of a particular object
+	// attribute can't be unified, or that the attribute types can't be
+	// unified as the element type of a map.
+	UnifyAttributeConflict
+
+	// UnifyElementsDiffer reports that a set of tuple types don't all have
+	// the same number of elements, so they can't be unified as a tuple type
+	// and so unification tries a list type instead.
+	UnifyElementsDiffer
+
+	// UnifyElementConflict reports that the types of a particular tuple
+	// element can't be unified, or that the element types can't be unified
+	// as the element type of a list.
+	UnifyElementConflict
+
+	// UnifyNoConversion reports that one of the types was not chosen as the
+	// result because another type can't be converted to it. This is
+	// reported only for sets of types that can't be unified at all.
+	UnifyNoConversion
+
+	// UnifyUnsafeConversion reports that the conversion from one of the
+	// types to the result type is unsafe, and so may fail for some values.
+	// This is reported only by UnifyUnsafeExplain.
+	UnifyUnsafeConversion
+)
+
+func (k UnifyStepKind) String() string {
+	switch k {
+	case UnifyResult:
+		return "result"
+	case UnifyDynamic:
+		return "dynamic"
+	case UnifyKindConflict:
+		return "kind conflict"
+	case UnifyAttributesDiffer:
+		return "attributes differ"
+	case UnifyAttributeConflict:
+		return "attribute conflict"
+	case UnifyElementsDiffer:
+		return "elements differ"
+	case UnifyElementConflict:
+		return "element conflict"
+	case UnifyNoConversion:
+		return "no conversion"
+	case UnifyUnsafeConversion:
+		return "unsafe conversion"
+	default:
+		return fmt.Sprintf("UnifyStepKind(%d)", int(k))
+	}
+}
+
+// String returns a description of the report with one step per line, each
+// prefixed by the path it relates to if that isn't the root of the types.
+// Index steps with unknown keys are written as [*], representing all of the
+// elements of a collection.
+func (r UnifyReport) String() string {
+	var buf strings.Builder
+	for _, step := range r.Steps {
+		if len(step.Path) != 0 {
+			buf.WriteString(cty.PathPatternFromTypePath(step.Path).String())
+			buf.WriteString(": ")
+		}
+		buf.WriteString(step.Message)
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// unifyExplainer records decisions made during unification in a UnifyReport.
+// A nil *unifyExplainer is valid and records nothing, which is how the
+// callers that don't need a report avoid the cost of producing one.
+type unifyExplainer struct {
+	report *UnifyReport
+	path   cty.Path
+
+	// inputs has an element for each of the types being unified at this
+	// level, giving the index of the top-level type it came from.
+	inputs []int
+}
+
+// nested returns an explainer for unifying types nested at the given step
+// beneath the types of the receiver, where each element of idxs is the index
+// of the receiver's type that each nested type came from.
+func (ex *unifyExplainer) nested(step cty.PathStep, idxs []int) *unifyExplainer {
+	if ex == nil {
+		return nil
+	}
+	path := make(cty.Path, len(ex.path), len(ex.path)+1)
+	copy(path, ex.path)
+	return &unifyExplainer{
+		report: ex.report,
+		path:   append(path, step),
+		inputs: ex.inputIndices(idxs),
+	}
+}
+
+// subset returns an explainer for unifying a subset of the types of the
+// receiver at the same path, where idxs are the indices of the types in the
+// subset.
+func (ex *unifyExplainer) subset(idxs []int) *unifyExplainer {
+	if ex == nil {
+		return nil
+	}
+	return &unifyExplainer{
+		report: ex.report,
+		path:   ex.path,
+		inputs: ex.inputIndices(idxs),
+	}
+}
+
+func (ex *unifyExplainer) inputIndices(idxs []int) []int {
+	ret := make([]int, len(idxs))
+	for i, idx := range idxs {
+		ret[i] = ex.inputs[idx]
+	}
+	return ret
+}
+
+// record adds a step to the report, where idxs are the indices of the types
+// at the receiver's level that the step relates to.
+func (ex *unifyExplainer) record(kind UnifyStepKind, idxs []int, f string, args ...any) {
+	if ex == nil {
+		return
+	}
+	var inputs []int
+	for _, input := range ex.inputIndices(idxs) {
+		if !containsInt(inputs, input) {
+			inputs = append(inputs, input)
+		}
+	}
+	sort.Ints(inputs)
+	ex.report.Steps = append(ex.report.Steps, UnifyStep{
+		Kind:    kind,
+		Path:    ex.path.Copy(),
+		Inputs:  inputs,
+		Message: fmt.Sprintf(f, args...),
+	})
+}
+
+func containsInt(s []int, v int) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
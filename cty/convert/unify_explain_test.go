@@ -0,0 +1,208 @@
+package convert
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestUnifyExplain(t *testing.T) {
+	type step struct {
+		Kind   UnifyStepKind
+		Path   string
+		Inputs []int
+	}
+	tests := []struct {
+		Input     []cty.Type
+		Unsafe    bool
+		WantType  cty.Type
+		WantSteps []step
+		WantText  string
+	}{
+		{
+			Input:    []cty.Type{cty.Number, cty.String},
+			WantType: cty.String,
+			WantSteps: []step{
+				{UnifyResult, "", []int{0, 1}},
+			},
+			WantText: "the types are unified as string\n",
+		},
+		{
+			Input:    []cty.Type{cty.Bool, cty.Number},
+			WantType: cty.NilType,
+			WantSteps: []step{
+				{UnifyNoConversion, "", []int{0, 1}},
+				{UnifyNoConversion, "", []int{0, 1}},
+			},
+			WantText: "cannot use bool, because there is no conversion to it from number\n" +
+				"cannot use number, because there is no conversion to it from bool\n",
+		},
+		{
+			Input:    []cty.Type{cty.String, cty.DynamicPseudoType},
+			WantType: cty.DynamicPseudoType,
+			WantSteps: []step{
+				{UnifyDynamic, "", []int{1}},
+				{UnifyResult, "", []int{0, 1}},
+			},
+			WantText: "the result must be dynamic because some of the types are not yet known\n" +
+				"the types are unified as dynamic\n",
+		},
+		{
+			Input:    []cty.Type{cty.String, cty.DynamicPseudoType},
+			Unsafe:   true,
+			WantType: cty.String,
+			WantSteps: []step{
+				{UnifyUnsafeConversion, "", []int{1}},
+				{UnifyResult, "", []int{0, 1}},
+			},
+			WantText: "the conversion from dynamic to string is unsafe\n" +
+				"the types are unified as string\n",
+		},
+		{
+			Input:    []cty.Type{cty.List(cty.String), cty.DynamicPseudoType, cty.List(cty.Number)},
+			WantType: cty.DynamicPseudoType,
+			WantSteps: []step{
+				{UnifyDynamic, "", []int{1}},
+				{UnifyResult, "", []int{0, 1, 2}},
+			},
+			WantText: "the result must be dynamic because some of the types are not yet known\n" +
+				"the types are unified as dynamic\n",
+		},
+		{
+			Input:    []cty.Type{cty.EmptyObject, cty.EmptyTuple},
+			WantType: cty.NilType,
+			WantSteps: []step{
+				{UnifyKindConflict, "", []int{0, 1}},
+			},
+			WantText: "object and tuple types cannot be unified\n",
+		},
+		{
+			Input: []cty.Type{
+				cty.Object(map[string]cty.Type{"a": cty.String}),
+				cty.Object(map[string]cty.Type{"a": cty.String, "b": cty.Number}),
+			},
+			WantType: cty.Map(cty.String),
+			WantSteps: []step{
+				{UnifyAttributesDiffer, "", []int{0}},
+				{UnifyResult, "", []int{0, 1}},
+			},
+			WantText: "object types have different attributes (\"b\"), so trying a map type instead\n" +
+				"the types are unified as map of string\n",
+		},
+		{
+			Input: []cty.Type{
+				cty.Object(map[string]cty.Type{"a": cty.String, "b": cty.Bool}),
+				cty.Object(map[string]cty.Type{"a": cty.String, "b": cty.Number}),
+			},
+			WantType: cty.NilType,
+			WantSteps: []step{
+				{UnifyNoConversion, "b", []int{0, 1}},
+				{UnifyNoConversion, "b", []int{0, 1}},
+				{UnifyAttributeConflict, "", []int{0, 1}},
+			},
+			WantText: "b: cannot use bool, because there is no conversion to it from number\n" +
+				"b: cannot use number, because there is no conversion to it from bool\n" +
+				"the types of attribute \"b\" cannot be unified\n",
+		},
+		{
+			Input: []cty.Type{
+				cty.Object(map[string]cty.Type{"a": cty.String}),
+				cty.Object(map[string]cty.Type{"b": cty.List(cty.String)}),
+			},
+			WantType: cty.NilType,
+			WantSteps: []step{
+				{UnifyAttributesDiffer, "", []int{0, 1}},
+				{UnifyNoConversion, "[*]", []int{0, 1}},
+				{UnifyNoConversion, "[*]", []int{0, 1}},
+				{UnifyAttributeConflict, "", []int{0, 1}},
+			},
+			WantText: "object types have different attributes (\"a\", \"b\"), so trying a map type instead\n" +
+				"[*]: cannot use string, because there is no conversion to it from list of string\n" +
+				"[*]: cannot use list of string, because there is no conversion to it from string\n" +
+				"the attribute types cannot all be unified as the element type of a map\n",
+		},
+		{
+			Input: []cty.Type{
+				cty.Tuple([]cty.Type{cty.String}),
+				cty.Tuple([]cty.Type{cty.String, cty.Number}),
+			},
+			WantType: cty.List(cty.String),
+			WantSteps: []step{
+				{UnifyElementsDiffer, "", []int{0, 1}},
+				{UnifyResult, "", []int{0, 1}},
+			},
+			WantText: "tuple types have different numbers of elements, so trying a list type instead\n" +
+				"the types are unified as list of string\n",
+		},
+		{
+			Input: []cty.Type{
+				cty.Tuple([]cty.Type{cty.String, cty.Bool}),
+				cty.Tuple([]cty.Type{cty.String, cty.Number}),
+			},
+			WantType: cty.NilType,
+			WantSteps: []step{
+				{UnifyNoConversion, "[1]", []int{0, 1}},
+				{UnifyNoConversion, "[1]", []int{0, 1}},
+				{UnifyElementConflict, "", []int{0, 1}},
+			},
+			WantText: "[1]: cannot use bool, because there is no conversion to it from number\n" +
+				"[1]: cannot use number, because there is no conversion to it from bool\n" +
+				"the types of element 1 cannot be unified\n",
+		},
+		{
+			Input: []cty.Type{
+				cty.List(cty.Object(map[string]cty.Type{"a": cty.String})),
+				cty.List(cty.Object(map[string]cty.Type{"b": cty.String})),
+			},
+			WantType: cty.List(cty.Map(cty.String)),
+			WantSteps: []step{
+				{UnifyAttributesDiffer, "[*]", []int{0, 1}},
+				{UnifyResult, "", []int{0, 1}},
+			},
+			WantText: "[*]: object types have different attributes (\"a\", \"b\"), so trying a map type instead\n" +
+				"the types are unified as list of map of string\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v (unsafe %t)", test.Input, test.Unsafe), func(t *testing.T) {
+			var gotType cty.Type
+			var gotConvs []Conversion
+			var report UnifyReport
+			if test.Unsafe {
+				gotType, gotConvs, report = UnifyUnsafeExplain(test.Input)
+			} else {
+				gotType, gotConvs, report = UnifyExplain(test.Input)
+			}
+
+			// The type and conversions must always match those from the
+			// variants that don't produce a report.
+			wantType, wantConvs := Unify(test.Input)
+			if test.Unsafe {
+				wantType, wantConvs = UnifyUnsafe(test.Input)
+			}
+			if !wantType.Equals(test.WantType) {
+				t.Fatalf("test expects wrong type %#v; Unify returns %#v", test.WantType, wantType)
+			}
+			if !gotType.Equals(wantType) {
+				t.Errorf("wrong type\ngot:  %#v\nwant: %#v", gotType, wantType)
+			}
+			if len(gotConvs) != len(wantConvs) {
+				t.Errorf("wrong number of conversions %d; want %d", len(gotConvs), len(wantConvs))
+			}
+
+			var gotSteps []step
+			for _, s := range report.Steps {
+				gotSteps = append(gotSteps, step{s.Kind, cty.PathPatternFromTypePath(s.Path).String(), s.Inputs})
+			}
+			if !reflect.DeepEqual(gotSteps, test.WantSteps) {
+				t.Errorf("wrong steps\ngot:  %#v\nwant: %#v", gotSteps, test.WantSteps)
+			}
+			if got := report.String(); got != test.WantText {
+				t.Errorf("wrong report\ngot:\n%s\nwant:\n%s", got, test.WantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package ctycompat
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Report describes the differences between two types, as returned by Compare
+// and CompareWithConverter.
+type Report struct {
+	// Changes are the differences between the types, in the order of a
+	// depth-first walk of the types with object attributes visited in
+	// lexical order. A change to a type that contains other types is
+	// reported before any changes to the types nested inside it.
+	Changes []Change
+}
+
+// Compatible returns true if none of the changes in the report are breaking,
+// and so all values of the old type can be used with the new type.
+func (r Report) Compatible() bool {
+	for _, change := range r.Changes {
+		if change.Breaking {
+			return false
+		}
+	}
+	return true
+}
+
+// BreakingChanges returns only the changes in the report that are breaking.
+func (r Report) BreakingChanges() []Change {
+	var ret []Change
+	for _, change := range r.Changes {
+		if change.Breaking {
+			ret = append(ret, change)
+		}
+	}
+	return ret
+}
+
+// String returns a description of the report with one change per line,
+// each prefixed by the path it relates to if that isn't the root of the
+// types. Index steps with unknown keys are written as [*], representing all
+// of the elements of a collection.
+func (r Report) String() string {
+	var buf strings.Builder
+	for _, change := range r.Changes {
+		if len(change.Path) != 0 {
+			buf.WriteString(cty.PathPatternFromTypePath(change.Path).String())
+			buf.WriteString(": ")
+		}
+		buf.WriteString(change.Message)
+		if change.Breaking {
+			buf.WriteString(" (breaking)")
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// Change describes a single difference between two types.
+type Change struct {
+	// Kind categorizes the change.
+	Kind ChangeKind
+
+	// Path is the location of the change within the types, using the same
+	// conventions as cty.WalkType: collection elements are represented by
+	// an IndexStep with an unknown key and tuple elements are represented by
+	// an IndexStep with a known number key. The key type for the elements of
+	// a collection is decided by the old type.
+	Path cty.Path
+
+	// Old and New are the types at Path in the old and new types
+	// respectively. Old is cty.NilType for an added attribute, and New is
+	// cty.NilType for a removed attribute.
+	Old, New cty.Type
+
+	// Breaking is true if the change means that some values of the old type
+	// can't be used with the new type, either because they can't be
+	// converted or because converting them would discard some of their data.
+	Breaking bool
+
+	// Message describes the change in English.
+	Message string
+}
+
+// ChangeKind categorizes the changes in a Report.
+type ChangeKind int
+
+const (
+	// TypeChanged reports that the type at a path was replaced by a type
+	// of a different kind, or by a different primitive type, or that a
+	// tuple type has a different number of elements.
+	TypeChanged ChangeKind = iota
+
+	// CollectionKindChanged reports that a collection type was replaced by
+	// a different kind of collection, such as a list replaced by a set. Any
+	// change to the element type is reported separately.
+	CollectionKindChanged
+
+	// AttributeAdded reports that the new object type has an attribute that
+	// the old type doesn't. This is breaking unless the attribute is
+	// optional.
+	AttributeAdded
+
+	// AttributeRemoved reports that the old object type has an attribute
+	// that the new type doesn't. This is always breaking, because converting
+	// a value to the new type would discard the attribute's value.
+	AttributeRemoved
+
+	// AttributeMadeOptional reports that an attribute that was required is
+	// now optional, which is never breaking.
+	AttributeMadeOptional
+
+	// AttributeMadeRequired reports that an attribute that was optional is
+	// now required, which is always breaking because existing values may
+	// omit the attribute.
+	AttributeMadeRequired
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case TypeChanged:
+		return "type changed"
+	case CollectionKindChanged:
+		return "collection kind changed"
+	case AttributeAdded:
+		return "attribute added"
+	case AttributeRemoved:
+		return "attribute removed"
+	case AttributeMadeOptional:
+		return "attribute made optional"
+	case AttributeMadeRequired:
+		return "attribute made required"
+	default:
+		return fmt.Sprintf("ChangeKind(%d)", int(k))
+	}
+}
@@ -0,0 +1,240 @@
+package ctycompat
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+var defaultConverter = convert.NewConverter(convert.Options{})
+
+// Compare returns a report of the changes from type old to type new, deciding
+// which of them are breaking using the built-in conversion rules of package
+// convert.
+//
+// Either type may be a type constraint with optional object attributes. Values
+// conforming to old may omit its optional attributes, and so an optional
+// attribute that becomes required is a breaking change.
+//
+// A change is breaking if there are values of the old type that either don't
+// have a safe conversion to the new type or that would lose data when
+// converted, such as the value of an attribute that the new type doesn't
+// have. Changes that only make the type more general, such as changing an
+// attribute from number to string, are reported but not breaking.
+func Compare(old, new cty.Type) Report {
+	return CompareWithConverter(old, new, defaultConverter)
+}
+
+// CompareWithConverter is like Compare except that it uses the given
+// converter to decide whether values of the old type can be converted to the
+// new type, and so takes account of the converter's options and custom rules.
+func CompareWithConverter(old, new cty.Type, conv *convert.Converter) Report {
+	c := &comparer{conv: conv}
+	c.compare(nil, old, new)
+	return Report{Changes: c.changes}
+}
+
+type comparer struct {
+	conv    *convert.Converter
+	changes []Change
+}
+
+func (c *comparer) compare(path cty.Path, old, new cty.Type) {
+	if old.Equals(new) {
+		return
+	}
+
+	switch {
+	case old.IsObjectType() && new.IsObjectType():
+		c.compareObjects(path, old, new)
+
+	case old.IsTupleType() && new.IsTupleType() && old.Length() == new.Length():
+		newEtys := new.TupleElementTypes()
+		for i, oldEty := range old.TupleElementTypes() {
+			c.compare(append(path.Copy(), cty.IndexStep{Key: cty.NumberIntVal(int64(i))}), oldEty, newEtys[i])
+		}
+
+	case old.IsTupleType() && new.IsTupleType():
+		c.add(Change{
+			Kind:     TypeChanged,
+			Path:     path.Copy(),
+			Old:      old,
+			New:      new,
+			Breaking: true,
+			Message:  fmt.Sprintf("number of tuple elements changed from %d to %d", old.Length(), new.Length()),
+		})
+
+	case old.IsCollectionType() && new.IsCollectionType():
+		if !sameCollectionKind(old, new) && !c.compareCollectionKinds(path, old, new) {
+			return
+		}
+		c.compare(append(path.Copy(), cty.CollectionElementStep(old)), old.ElementType(), new.ElementType())
+
+	default:
+		c.compareTypes(path, old, new)
+	}
+}
+
+func (c *comparer) compareObjects(path cty.Path, old, new cty.Type) {
+	oldAtys := old.AttributeTypes()
+	newAtys := new.AttributeTypes()
+
+	names := make([]string, 0, len(oldAtys)+len(newAtys))
+	for name := range oldAtys {
+		names = append(names, name)
+	}
+	for name := range newAtys {
+		if _, exists := oldAtys[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		attrPath := append(path.Copy(), cty.GetAttrStep{Name: name})
+		oldAty, inOld := oldAtys[name]
+		newAty, inNew := newAtys[name]
+
+		switch {
+		case !inNew:
+			c.add(Change{
+				Kind:     AttributeRemoved,
+				Path:     attrPath,
+				Old:      oldAty,
+				New:      cty.NilType,
+				Breaking: true,
+				Message:  "attribute removed",
+			})
+			continue
+
+		case !inOld:
+			optional := new.AttributeOptional(name)
+			msg := "required attribute added"
+			if optional {
+				msg = "optional attribute added"
+			}
+			c.add(Change{
+				Kind:     AttributeAdded,
+				Path:     attrPath,
+				Old:      cty.NilType,
+				New:      newAty,
+				Breaking: !optional,
+				Message:  msg,
+			})
+			continue
+		}
+
+		switch oldOpt, newOpt := old.AttributeOptional(name), new.AttributeOptional(name); {
+		case oldOpt && !newOpt:
+			c.add(Change{
+				Kind:     AttributeMadeRequired,
+				Path:     attrPath,
+				Old:      oldAty,
+				New:      newAty,
+				Breaking: true,
+				Message:  "attribute changed from optional to required",
+			})
+		case !oldOpt && newOpt:
+			c.add(Change{
+				Kind:    AttributeMadeOptional,
+				Path:    attrPath,
+				Old:     oldAty,
+				New:     newAty,
+				Message: "attribute changed from required to optional",
+			})
+		}
+		c.compare(attrPath, oldAty, newAty)
+	}
+}
+
+// compareCollectionKinds reports the change between two different kinds of
+// collection, and returns true if it is possible to convert between them
+// so that it's also meaningful to compare their element types.
+func (c *comparer) compareCollectionKinds(path cty.Path, old, new cty.Type) bool {
+	// We consider only the kinds of collection here, leaving any change in
+	// element type to be reported separately.
+	class := c.conv.CheckAssignability(valueType(old), collectionOf(new, old.ElementType())).Class
+	if class == convert.AssignImpossible {
+		c.compareTypes(path, old, new)
+		return false
+	}
+	c.add(Change{
+		Kind:     CollectionKindChanged,
+		Path:     path.Copy(),
+		Old:      old,
+		New:      new,
+		Breaking: class < convert.AssignSafe,
+		Message:  fmt.Sprintf("changed from %s to %s", collectionKindName(old), collectionKindName(new)),
+	})
+	return true
+}
+
+// compareTypes reports the change between two types that can't be compared
+// in any more detail, based only on whether values of one can be converted to
+// the other.
+func (c *comparer) compareTypes(path cty.Path, old, new cty.Type) {
+	var breaking bool
+	var msg string
+	switch c.conv.CheckAssignability(valueType(old), new).Class {
+	case convert.AssignIdentical:
+		return
+	case convert.AssignSafe:
+		msg = fmt.Sprintf("changed from %s to %s", old.FriendlyName(), new.FriendlyName())
+	case convert.AssignUnsafe:
+		breaking = true
+		msg = fmt.Sprintf("changed from %s to %s, which some existing values may not convert to", old.FriendlyName(), new.FriendlyName())
+	default:
+		breaking = true
+		msg = fmt.Sprintf("changed from %s to %s, which existing values can't convert to", old.FriendlyName(), new.FriendlyName())
+	}
+	c.add(Change{
+		Kind:     TypeChanged,
+		Path:     path.Copy(),
+		Old:      old,
+		New:      new,
+		Breaking: breaking,
+		Message:  msg,
+	})
+}
+
+func (c *comparer) add(change Change) {
+	c.changes = append(c.changes, change)
+}
+
+// valueType returns the type of the values that conform to the given type
+// constraint, which never have optional attributes.
+func valueType(ty cty.Type) cty.Type {
+	return ty.WithoutOptionalAttributesDeep()
+}
+
+func sameCollectionKind(a, b cty.Type) bool {
+	return a.IsListType() && b.IsListType() ||
+		a.IsSetType() && b.IsSetType() ||
+		a.IsMapType() && b.IsMapType()
+}
+
+// collectionOf returns a collection type of the same kind as the given
+// collection type, with the given element type.
+func collectionOf(kind cty.Type, ety cty.Type) cty.Type {
+	switch {
+	case kind.IsListType():
+		return cty.List(ety)
+	case kind.IsSetType():
+		return cty.Set(ety)
+	default:
+		return cty.Map(ety)
+	}
+}
+
+func collectionKindName(ty cty.Type) string {
+	switch {
+	case ty.IsListType():
+		return "list"
+	case ty.IsSetType():
+		return "set"
+	default:
+		return "map"
+	}
+}
@@ -0,0 +1,198 @@
+package ctycompat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+func TestCompare(t *testing.T) {
+	tests := map[string]struct {
+		Old, New   cty.Type
+		Want       string
+		Compatible bool
+	}{
+		"identical": {
+			Old:        cty.List(cty.String),
+			New:        cty.List(cty.String),
+			Want:       "",
+			Compatible: true,
+		},
+		"widened primitive": {
+			Old:        cty.Number,
+			New:        cty.String,
+			Want:       "changed from number to string\n",
+			Compatible: true,
+		},
+		"narrowed primitive": {
+			Old:  cty.String,
+			New:  cty.Number,
+			Want: "changed from string to number, which some existing values may not convert to (breaking)\n",
+		},
+		"incompatible primitive": {
+			Old:  cty.Bool,
+			New:  cty.Number,
+			Want: "changed from bool to number, which existing values can't convert to (breaking)\n",
+		},
+		"to dynamic": {
+			Old:        cty.Map(cty.Bool),
+			New:        cty.DynamicPseudoType,
+			Want:       "changed from map of bool to dynamic\n",
+			Compatible: true,
+		},
+		"narrowed element type": {
+			Old:  cty.List(cty.String),
+			New:  cty.List(cty.Number),
+			Want: "[*]: changed from string to number, which some existing values may not convert to (breaking)\n",
+		},
+		"set to list": {
+			Old:        cty.Set(cty.Number),
+			New:        cty.List(cty.String),
+			Want:       "changed from set to list\n[*]: changed from number to string\n",
+			Compatible: true,
+		},
+		"list to set": {
+			Old:  cty.List(cty.String),
+			New:  cty.Set(cty.String),
+			Want: "changed from list to set (breaking)\n",
+		},
+		"list to map": {
+			Old:  cty.List(cty.String),
+			New:  cty.Map(cty.String),
+			Want: "changed from list of string to map of string, which existing values can't convert to (breaking)\n",
+		},
+		"tuple length": {
+			Old:  cty.Tuple([]cty.Type{cty.String}),
+			New:  cty.Tuple([]cty.Type{cty.String, cty.String}),
+			Want: "number of tuple elements changed from 1 to 2 (breaking)\n",
+		},
+		"tuple elements": {
+			Old:        cty.Tuple([]cty.Type{cty.String, cty.Bool}),
+			New:        cty.Tuple([]cty.Type{cty.String, cty.String}),
+			Want:       "[1]: changed from bool to string\n",
+			Compatible: true,
+		},
+		"attributes": {
+			Old: cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"removed":      cty.String,
+				"kept":         cty.String,
+				"now_optional": cty.String,
+				"now_required": cty.String,
+			}, []string{"now_required"}),
+			New: cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"kept":         cty.String,
+				"now_optional": cty.String,
+				"now_required": cty.String,
+				"new_optional": cty.String,
+				"new_required": cty.String,
+			}, []string{"now_optional", "new_optional"}),
+			Want: `new_optional: optional attribute added
+new_required: required attribute added (breaking)
+now_optional: attribute changed from required to optional
+now_required: attribute changed from optional to required (breaking)
+removed: attribute removed (breaking)
+`,
+		},
+		"nested": {
+			Old: cty.Object(map[string]cty.Type{
+				"rules": cty.List(cty.Object(map[string]cty.Type{
+					"port": cty.Number,
+					"tags": cty.Map(cty.String),
+				})),
+			}),
+			New: cty.Object(map[string]cty.Type{
+				"rules": cty.List(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+					"port":    cty.String,
+					"tags":    cty.Map(cty.Bool),
+					"comment": cty.String,
+				}, []string{"comment"})),
+			}),
+			Want: `rules[*].comment: optional attribute added
+rules[*].port: changed from number to string
+rules[*].tags[*]: changed from string to bool, which some existing values may not convert to (breaking)
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			report := Compare(test.Old, test.New)
+			if got := report.String(); got != test.Want {
+				t.Errorf("wrong report\ngot:\n%s\nwant:\n%s", got, test.Want)
+			}
+			if got := report.Compatible(); got != test.Compatible {
+				t.Errorf("wrong compatibility %t; want %t", got, test.Compatible)
+			}
+			if got, want := len(report.BreakingChanges()) == 0, test.Compatible; got != want {
+				t.Errorf("wrong breaking changes %#v", report.BreakingChanges())
+			}
+		})
+	}
+}
+
+func TestCompareChange(t *testing.T) {
+	report := Compare(
+		cty.Object(map[string]cty.Type{"a": cty.Set(cty.String)}),
+		cty.Object(map[string]cty.Type{"a": cty.Set(cty.Number)}),
+	)
+	if len(report.Changes) != 1 {
+		t.Fatalf("wrong number of changes %d; want 1", len(report.Changes))
+	}
+	change := report.Changes[0]
+	wantPath := cty.GetAttrPath("a").Index(cty.UnknownVal(cty.String))
+	if !change.Path.Equals(wantPath) {
+		t.Errorf("wrong path\ngot:  %#v\nwant: %#v", change.Path, wantPath)
+	}
+	if change.Kind != TypeChanged {
+		t.Errorf("wrong kind %s", change.Kind)
+	}
+	if !change.Old.Equals(cty.String) || !change.New.Equals(cty.Number) {
+		t.Errorf("wrong types %#v and %#v", change.Old, change.New)
+	}
+	if !change.Breaking {
+		t.Errorf("change is not breaking")
+	}
+}
+
+func TestCompareWithConverter(t *testing.T) {
+	dateTy := cty.Object(map[string]cty.Type{
+		"year":  cty.Number,
+		"month": cty.Number,
+		"day":   cty.Number,
+	})
+	conv := convert.NewConverter(convert.Options{}, convert.Rule{
+		GetConversion: func(in, out cty.Type) (convert.Conversion, bool) {
+			if !in.Equals(cty.String) || !out.Equals(dateTy) {
+				return nil, false
+			}
+			return func(in cty.Value) (cty.Value, error) {
+				t, err := time.Parse(time.RFC3339, in.AsString())
+				if err != nil {
+					return cty.NilVal, err
+				}
+				return cty.ObjectVal(map[string]cty.Value{
+					"year":  cty.NumberIntVal(int64(t.Year())),
+					"month": cty.NumberIntVal(int64(t.Month())),
+					"day":   cty.NumberIntVal(int64(t.Day())),
+				}), nil
+			}, false
+		},
+	})
+
+	old := cty.Object(map[string]cty.Type{"created": cty.String})
+	new := cty.Object(map[string]cty.Type{"created": dateTy})
+
+	if got, want := Compare(old, new).String(), "created: changed from string to object, which existing values can't convert to (breaking)\n"; got != want {
+		t.Errorf("wrong report without the rule\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := CompareWithConverter(old, new, conv).String(), "created: changed from string to object, which some existing values may not convert to (breaking)\n"; got != want {
+		t.Errorf("wrong report with the rule\ngot:  %s\nwant: %s", got, want)
+	}
+
+	strict := convert.NewConverter(convert.Strict)
+	if got, want := CompareWithConverter(cty.Number, cty.String, strict).String(), "changed from number to string, which existing values can't convert to (breaking)\n"; got != want {
+		t.Errorf("wrong report with strict options\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,8 @@
+// Package ctycompat compares two versions of a type, such as the types of a
+// versioned resource schema, to decide whether values conforming to the older
+// type can still be used with the newer type.
+//
+// The comparison uses the conversion rules of package convert, and so a
+// change is considered compatible if every value of the old type has a safe
+// conversion to the new type that doesn't discard any data.
+package ctycompat
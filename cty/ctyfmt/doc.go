@@ -0,0 +1,9 @@
+// Package ctyfmt renders values and types in an indented, human-oriented
+// notation that resembles HCL, for use in logs and user interfaces.
+//
+// Unlike the result of Value.GoString, the output of this package is not
+// intended to be parsed or evaluated, and its exact formatting may change in
+// future versions in order to improve readability. Applications that need a
+// machine-readable representation should use package json or msgpack
+// instead.
+package ctyfmt
@@ -0,0 +1,73 @@
+package ctyfmt
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Options customizes the behavior of FormatValue and FormatType.
+//
+// The zero value of Options selects the default behavior, which renders the
+// entire given value or type using an indentation of two spaces.
+type Options struct {
+	// Indent is the string used for each level of indentation. If empty,
+	// two spaces are used.
+	Indent string
+
+	// MaxDepth, if greater than zero, limits the number of levels of nested
+	// collection and structural values that will be rendered. Any such value
+	// nested deeper than the limit is rendered as a placeholder instead of
+	// showing its elements.
+	MaxDepth int
+
+	// MaxElements, if greater than zero, limits the number of elements or
+	// attributes rendered for any single collection or structural value.
+	// Any remaining elements are summarized by a comment giving their count.
+	MaxElements int
+
+	// SortSets causes the elements of sets to be rendered in the order
+	// defined by cty.Compare, rather than in the set's internal order. The
+	// internal order is stable for a given set, but depends on the hashes of
+	// the elements and so isn't meaningful to a reader.
+	//
+	// Sets whose element type contains a capsule type that doesn't implement
+	// the Compare capsule operation can't be sorted, and so are always
+	// rendered in their internal order.
+	SortSets bool
+
+	// MapKeyOrder, if set, defines the order in which the elements of maps
+	// are rendered, returning a negative number if key a should be rendered
+	// before key b, a positive number if it should be rendered after, or
+	// zero if their order doesn't matter. If MapKeyOrder is nil then map
+	// elements are rendered in lexical order of their keys.
+	//
+	// Object attributes are always rendered in lexical order of their names.
+	MapKeyOrder func(a, b string) int
+
+	// UnknownText is the text used to describe an unknown value. If empty,
+	// "known after apply" is used.
+	UnknownText string
+
+	// RenderMarks, if set, is called for each value that has marks, with the
+	// value and its direct marks. If it returns true, the returned string is
+	// rendered in place of the value, which allows hiding values with marks
+	// such as a "sensitive" mark. If it returns false, the value is rendered
+	// as if it were not marked.
+	//
+	// The returned string is written verbatim, so it should not contain any
+	// newlines. If RenderMarks is nil then all marks are ignored.
+	RenderMarks func(val cty.Value, marks cty.ValueMarks) (string, bool)
+}
+
+func (o *Options) indent() string {
+	if o.Indent == "" {
+		return "  "
+	}
+	return o.Indent
+}
+
+func (o *Options) unknownText() string {
+	if o.UnknownText == "" {
+		return "known after apply"
+	}
+	return o.UnknownText
+}
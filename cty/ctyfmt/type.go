@@ -0,0 +1,107 @@
+package ctyfmt
+
+import (
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// FormatType returns a human-readable, possibly multi-line representation of
+// the given type, using the same notation as cty.Type.TypeString but with
+// each object attribute and tuple element on its own line:
+//
+//	list(object({
+//	  name = string
+//	  port = optional(number)
+//	}))
+//
+// The MaxDepth and MaxElements options limit how much of a deeply-nested or
+// large object or tuple type is rendered, as for FormatValue. The options
+// related only to values are ignored.
+func FormatType(ty cty.Type, opts Options) string {
+	f := &formatter{opts: &opts}
+	f.typ(ty, 0)
+	return f.buf.String()
+}
+
+func (f *formatter) typ(ty cty.Type, depth int) {
+	switch {
+	case ty.IsListType():
+		f.buf.WriteString("list(")
+		f.typ(ty.ElementType(), depth)
+		f.buf.WriteByte(')')
+	case ty.IsMapType():
+		f.buf.WriteString("map(")
+		f.typ(ty.ElementType(), depth)
+		f.buf.WriteByte(')')
+	case ty.IsSetType():
+		f.buf.WriteString("set(")
+		f.typ(ty.ElementType(), depth)
+		f.buf.WriteByte(')')
+	case ty.IsObjectType():
+		atys := ty.AttributeTypes()
+		switch {
+		case len(atys) == 0:
+			f.buf.WriteString("object({})")
+			return
+		case f.elided(depth):
+			f.buf.WriteString("object({...})")
+			return
+		}
+
+		names := make([]string, 0, len(atys))
+		for name := range atys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		n := f.shown(len(names))
+		width := 0
+		for _, name := range names[:n] {
+			width = max(width, labelWidth(attrLabel(name)))
+		}
+
+		f.buf.WriteString("object({")
+		for _, name := range names[:n] {
+			label := attrLabel(name)
+			f.newline(depth + 1)
+			f.buf.WriteString(label)
+			for range width - labelWidth(label) {
+				f.buf.WriteByte(' ')
+			}
+			f.buf.WriteString(" = ")
+			if ty.AttributeOptional(name) {
+				f.buf.WriteString("optional(")
+				f.typ(atys[name], depth+1)
+				f.buf.WriteByte(')')
+			} else {
+				f.typ(atys[name], depth+1)
+			}
+		}
+		f.more(len(names)-n, "attribute", depth+1)
+		f.newline(depth)
+		f.buf.WriteString("})")
+	case ty.IsTupleType():
+		etys := ty.TupleElementTypes()
+		switch {
+		case len(etys) == 0:
+			f.buf.WriteString("tuple([])")
+			return
+		case f.elided(depth):
+			f.buf.WriteString("tuple([...])")
+			return
+		}
+
+		n := f.shown(len(etys))
+		f.buf.WriteString("tuple([")
+		for _, ety := range etys[:n] {
+			f.newline(depth + 1)
+			f.typ(ety, depth+1)
+			f.buf.WriteByte(',')
+		}
+		f.more(len(etys)-n, "element", depth+1)
+		f.newline(depth)
+		f.buf.WriteString("])")
+	default:
+		f.buf.WriteString(ty.TypeString())
+	}
+}
@@ -0,0 +1,76 @@
+package ctyfmt
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestFormatType(t *testing.T) {
+	tests := map[string]struct {
+		Type cty.Type
+		Opts Options
+		Want string
+	}{
+		"primitive": {
+			Type: cty.String,
+			Want: `string`,
+		},
+		"dynamic": {
+			Type: cty.DynamicPseudoType,
+			Want: `any`,
+		},
+		"empty object": {
+			Type: cty.EmptyObject,
+			Want: `object({})`,
+		},
+		"nested": {
+			Type: cty.List(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"name":   cty.String,
+				"port":   cty.Number,
+				"extras": cty.Tuple([]cty.Type{cty.Bool, cty.Map(cty.String)}),
+			}, []string{"port"})),
+			Want: `list(object({
+  extras = tuple([
+    bool,
+    map(string),
+  ])
+  name   = string
+  port   = optional(number)
+}))`,
+		},
+		"max depth": {
+			Type: cty.Object(map[string]cty.Type{
+				"a": cty.Object(map[string]cty.Type{"b": cty.String}),
+			}),
+			Opts: Options{MaxDepth: 1},
+			Want: `object({
+  a = object({...})
+})`,
+		},
+		"labels with combining characters": {
+			Type: cty.Object(map[string]cty.Type{
+				"x\u0301": cty.String,
+				"yz":      cty.Number,
+			}),
+			Want: "object({\n  \"x\u0301\" = string\n  yz  = number\n})",
+		},
+		"max elements": {
+			Type: cty.Tuple([]cty.Type{cty.String, cty.String, cty.String}),
+			Opts: Options{MaxElements: 1},
+			Want: `tuple([
+  string,
+  # (2 more elements)
+])`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := FormatType(test.Type, test.Opts)
+			if got != test.Want {
+				t.Errorf("wrong result\ngot:\n%s\nwant:\n%s", got, test.Want)
+			}
+		})
+	}
+}
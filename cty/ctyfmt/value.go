@@ -0,0 +1,314 @@
+package ctyfmt
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/apparentlymart/go-textseg/v15/textseg"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// FormatValue returns a human-readable, possibly multi-line representation
+// of the given value.
+//
+// Objects and maps are rendered as blocks of "key = value" lines, and lists,
+// sets, and tuples as bracketed sequences with one element per line:
+//
+//	{
+//	  name  = "web"
+//	  ports = [
+//	    80,
+//	    443,
+//	  ]
+//	  tags  = {
+//	    "env" = "prod"
+//	  }
+//	}
+//
+// Unknown values are described along with any refinements they have, such as
+// (known after apply, not null, prefix "ab"). Marks are ignored unless the
+// given options include a RenderMarks function.
+//
+// The result has no trailing newline, and can be written with additional
+// indentation by replacing each newline with a newline followed by the
+// additional indentation.
+func FormatValue(val cty.Value, opts Options) string {
+	f := &formatter{opts: &opts}
+	f.value(val, 0)
+	return f.buf.String()
+}
+
+type formatter struct {
+	buf  strings.Builder
+	opts *Options
+}
+
+func (f *formatter) newline(depth int) {
+	f.buf.WriteByte('\n')
+	for range depth {
+		f.buf.WriteString(f.opts.indent())
+	}
+}
+
+// elided returns true if a collection or structural value at the given
+// depth is too deep to render its elements.
+func (f *formatter) elided(depth int) bool {
+	return f.opts.MaxDepth > 0 && depth >= f.opts.MaxDepth
+}
+
+// shown returns how many of the given number of elements should be rendered.
+func (f *formatter) shown(n int) int {
+	if f.opts.MaxElements > 0 && n > f.opts.MaxElements {
+		return f.opts.MaxElements
+	}
+	return n
+}
+
+// more writes a comment summarizing the given number of elements that
+// were not rendered, if any.
+func (f *formatter) more(n int, noun string, depth int) {
+	if n <= 0 {
+		return
+	}
+	f.newline(depth)
+	if n == 1 {
+		fmt.Fprintf(&f.buf, "# (1 more %s)", noun)
+	} else {
+		fmt.Fprintf(&f.buf, "# (%d more %ss)", n, noun)
+	}
+}
+
+func (f *formatter) value(val cty.Value, depth int) {
+	if val.IsMarked() {
+		var marks cty.ValueMarks
+		val, marks = val.Unmark()
+		if f.opts.RenderMarks != nil {
+			if s, ok := f.opts.RenderMarks(val.WithMarks(marks), marks); ok {
+				f.buf.WriteString(s)
+				return
+			}
+		}
+	}
+
+	switch {
+	case !val.IsKnown():
+		f.unknown(val)
+		return
+	case val.IsNull():
+		f.buf.WriteString("null")
+		return
+	}
+
+	ty := val.Type()
+	switch {
+	case ty == cty.Bool:
+		if val.True() {
+			f.buf.WriteString("true")
+		} else {
+			f.buf.WriteString("false")
+		}
+	case ty == cty.Number:
+		f.buf.WriteString(val.AsBigFloat().Text('f', -1))
+	case ty == cty.String:
+		f.buf.WriteString(strconv.Quote(val.AsString()))
+	case ty.IsListType() || ty.IsTupleType():
+		f.sequence(val.AsValueSlice(), depth)
+	case ty.IsSetType():
+		elems := val.AsValueSlice()
+		if f.opts.SortSets && sortable(ty.ElementType()) {
+			sort.SliceStable(elems, func(i, j int) bool {
+				return cty.Compare(elems[i], elems[j]) < 0
+			})
+		}
+		f.sequence(elems, depth)
+	case ty.IsMapType():
+		m := val.AsValueMap()
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		if f.opts.MapKeyOrder != nil {
+			sort.SliceStable(keys, func(i, j int) bool {
+				return f.opts.MapKeyOrder(keys[i], keys[j]) < 0
+			})
+		} else {
+			sort.Strings(keys)
+		}
+		labels := make([]string, len(keys))
+		for i, k := range keys {
+			labels[i] = strconv.Quote(k)
+		}
+		f.mapping(keys, labels, m, "element", depth)
+	case ty.IsObjectType():
+		m := val.AsValueMap()
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		labels := make([]string, len(keys))
+		for i, k := range keys {
+			labels[i] = attrLabel(k)
+		}
+		f.mapping(keys, labels, m, "attribute", depth)
+	default:
+		// Capsule types have no notation of their own, so we'll use their
+		// GoString representation, which they can customize.
+		f.buf.WriteString(val.GoString())
+	}
+}
+
+func (f *formatter) sequence(elems []cty.Value, depth int) {
+	switch {
+	case len(elems) == 0:
+		f.buf.WriteString("[]")
+		return
+	case f.elided(depth):
+		f.buf.WriteString("[...]")
+		return
+	}
+
+	f.buf.WriteByte('[')
+	n := f.shown(len(elems))
+	for _, ev := range elems[:n] {
+		f.newline(depth + 1)
+		f.value(ev, depth+1)
+		f.buf.WriteByte(',')
+	}
+	f.more(len(elems)-n, "element", depth+1)
+	f.newline(depth)
+	f.buf.WriteByte(']')
+}
+
+func (f *formatter) mapping(keys, labels []string, m map[string]cty.Value, noun string, depth int) {
+	switch {
+	case len(keys) == 0:
+		f.buf.WriteString("{}")
+		return
+	case f.elided(depth):
+		f.buf.WriteString("{...}")
+		return
+	}
+
+	n := f.shown(len(keys))
+	widths := make([]int, n)
+	width := 0
+	for i, label := range labels[:n] {
+		widths[i] = labelWidth(label)
+		width = max(width, widths[i])
+	}
+
+	f.buf.WriteByte('{')
+	for i, k := range keys[:n] {
+		f.newline(depth + 1)
+		f.buf.WriteString(labels[i])
+		f.buf.WriteString(strings.Repeat(" ", width-widths[i]))
+		f.buf.WriteString(" = ")
+		f.value(m[k], depth+1)
+	}
+	f.more(len(keys)-n, noun, depth+1)
+	f.newline(depth)
+	f.buf.WriteByte('}')
+}
+
+func (f *formatter) unknown(val cty.Value) {
+	f.buf.WriteByte('(')
+	f.buf.WriteString(f.opts.unknownText())
+
+	rng := val.Range()
+	if rng.DefinitelyNotNull() {
+		f.buf.WriteString(", not null")
+	}
+	ty := val.Type()
+	switch {
+	case ty == cty.String:
+		if prefix := rng.StringPrefix(); prefix != "" {
+			f.buf.WriteString(", prefix ")
+			f.buf.WriteString(strconv.Quote(prefix))
+		}
+	case ty == cty.Number:
+		if lower, inclusive := rng.NumberLowerBound(); lower.IsKnown() && !lower.RawEquals(cty.NegativeInfinity) {
+			if inclusive {
+				f.buf.WriteString(", >= ")
+			} else {
+				f.buf.WriteString(", > ")
+			}
+			f.buf.WriteString(lower.AsBigFloat().Text('f', -1))
+		}
+		if upper, inclusive := rng.NumberUpperBound(); upper.IsKnown() && !upper.RawEquals(cty.PositiveInfinity) {
+			if inclusive {
+				f.buf.WriteString(", <= ")
+			} else {
+				f.buf.WriteString(", < ")
+			}
+			f.buf.WriteString(upper.AsBigFloat().Text('f', -1))
+		}
+	case ty.IsCollectionType():
+		lower, upper := rng.LengthLowerBound(), rng.LengthUpperBound()
+		switch {
+		case lower == upper:
+			fmt.Fprintf(&f.buf, ", length %d", lower)
+		default:
+			if lower > 0 {
+				fmt.Fprintf(&f.buf, ", length >= %d", lower)
+			}
+			if upper != math.MaxInt {
+				fmt.Fprintf(&f.buf, ", length <= %d", upper)
+			}
+		}
+	}
+	f.buf.WriteByte(')')
+}
+
+// labelWidth returns the number of grapheme clusters in the given label,
+// which approximates the number of columns it occupies when displayed, for
+// aligning the equals signs after a series of labels.
+func labelWidth(label string) int {
+	n, _ := textseg.TokenCount([]byte(label), textseg.ScanGraphemeClusters)
+	return n
+}
+
+// sortable returns true if cty.Compare can be used with values of the
+// given type, which is the case unless it contains a capsule type that
+// doesn't implement the Compare capsule operation.
+func sortable(ty cty.Type) bool {
+	ret := true
+	cty.WalkType(ty, func(_ cty.Path, ty cty.Type, _ bool) (bool, error) {
+		if ty.IsCapsuleType() {
+			if ops := ty.CapsuleOps(); ops == nil || ops.Compare == nil {
+				ret = false
+			}
+		}
+		return ret, nil
+	})
+	return ret
+}
+
+// attrLabel returns the given attribute name as it should appear before the
+// equals sign, which is quoted only if it isn't a valid identifier.
+func attrLabel(name string) string {
+	if isIdent(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || unicode.IsLetter(r):
+		case i > 0 && (r == '-' || unicode.IsDigit(r)):
+		default:
+			return false
+		}
+	}
+	return true
+}
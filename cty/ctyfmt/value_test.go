@@ -0,0 +1,222 @@
+package ctyfmt
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestFormatValue(t *testing.T) {
+	tests := map[string]struct {
+		Value cty.Value
+		Opts  Options
+		Want  string
+	}{
+		"string": {
+			Value: cty.StringVal("hello\n\"world\""),
+			Want:  `"hello\n\"world\""`,
+		},
+		"number": {
+			Value: cty.NumberFloatVal(1.5),
+			Want:  `1.5`,
+		},
+		"bool": {
+			Value: cty.True,
+			Want:  `true`,
+		},
+		"null": {
+			Value: cty.NullVal(cty.List(cty.String)),
+			Want:  `null`,
+		},
+		"empty list": {
+			Value: cty.ListValEmpty(cty.String),
+			Want:  `[]`,
+		},
+		"empty object": {
+			Value: cty.EmptyObjectVal,
+			Want:  `{}`,
+		},
+		"nested": {
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.StringVal("web"),
+				"ports": cty.ListVal([]cty.Value{cty.NumberIntVal(80), cty.NumberIntVal(443)}),
+				"tags": cty.MapVal(map[string]cty.Value{
+					"env":      cty.StringVal("prod"),
+					"cost-ctr": cty.StringVal("1234"),
+				}),
+				"odd name": cty.NullVal(cty.Bool),
+			}),
+			Want: `{
+  name       = "web"
+  "odd name" = null
+  ports      = [
+    80,
+    443,
+  ]
+  tags       = {
+    "cost-ctr" = "1234"
+    "env"      = "prod"
+  }
+}`,
+		},
+		"custom indent": {
+			Value: cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.EmptyTupleVal}),
+			Opts:  Options{Indent: "\t"},
+			Want:  "[\n\t\"a\",\n\t[],\n]",
+		},
+		"max depth": {
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"a": cty.ObjectVal(map[string]cty.Value{
+					"b": cty.ListVal([]cty.Value{cty.StringVal("c")}),
+					"d": cty.StringVal("e"),
+				}),
+			}),
+			Opts: Options{MaxDepth: 2},
+			Want: `{
+  a = {
+    b = [...]
+    d = "e"
+  }
+}`,
+		},
+		"max elements": {
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"a": cty.ListVal([]cty.Value{cty.Zero, cty.Zero, cty.Zero}),
+				"b": cty.ListVal([]cty.Value{cty.Zero, cty.Zero}),
+				"c": cty.ListVal([]cty.Value{cty.Zero}),
+			}),
+			Opts: Options{MaxElements: 2},
+			Want: `{
+  a = [
+    0,
+    0,
+    # (1 more element)
+  ]
+  b = [
+    0,
+    0,
+  ]
+  # (1 more attribute)
+}`,
+		},
+		"sorted set": {
+			Value: cty.SetVal([]cty.Value{cty.StringVal("c"), cty.StringVal("a"), cty.StringVal("b")}),
+			Opts:  Options{SortSets: true},
+			Want: `[
+  "a",
+  "b",
+  "c",
+]`,
+		},
+		"map with custom key order": {
+			Value: cty.MapVal(map[string]cty.Value{
+				"a":   cty.True,
+				"bbb": cty.False,
+				"cc":  cty.True,
+			}),
+			Opts: Options{
+				MapKeyOrder: func(a, b string) int {
+					return len(b) - len(a)
+				},
+			},
+			Want: `{
+  "bbb" = false
+  "cc"  = true
+  "a"   = true
+}`,
+		},
+		"labels with combining characters": {
+			Value: cty.MapVal(map[string]cty.Value{
+				"x\u0301": cty.True,
+				"yz":      cty.False,
+			}),
+			Want: "{\n  \"x\u0301\"  = true\n  \"yz\" = false\n}",
+		},
+		"unknown": {
+			Value: cty.UnknownVal(cty.String),
+			Want:  `(known after apply)`,
+		},
+		"unknown with custom text": {
+			Value: cty.DynamicVal,
+			Opts:  Options{UnknownText: "pending"},
+			Want:  `(pending)`,
+		},
+		"unknown string with prefix": {
+			Value: cty.UnknownVal(cty.String).Refine().NotNull().StringPrefixFull("ab").NewValue(),
+			Want:  `(known after apply, not null, prefix "ab")`,
+		},
+		"unknown number with bounds": {
+			Value: cty.UnknownVal(cty.Number).Refine().
+				NumberRangeLowerBound(cty.Zero, true).
+				NumberRangeUpperBound(cty.NumberIntVal(10), false).
+				NewValue(),
+			Want: `(known after apply, >= 0, < 10)`,
+		},
+		"unknown list with length bounds": {
+			Value: cty.UnknownVal(cty.List(cty.String)).Refine().
+				CollectionLengthLowerBound(1).
+				CollectionLengthUpperBound(3).
+				NewValue(),
+			Want: `(known after apply, length >= 1, length <= 3)`,
+		},
+		"unknown list with exact length": {
+			Value: cty.UnknownVal(cty.List(cty.String)).Refine().CollectionLength(2).NewValue(),
+			Want:  `(known after apply, length 2)`,
+		},
+		"marks ignored by default": {
+			Value: cty.StringVal("secret").Mark("sensitive"),
+			Want:  `"secret"`,
+		},
+		"marks rendered": {
+			Value: cty.ObjectVal(map[string]cty.Value{
+				"user":     cty.StringVal("admin").Mark("other"),
+				"password": cty.StringVal("secret").Mark("sensitive"),
+			}),
+			Opts: Options{
+				RenderMarks: func(val cty.Value, marks cty.ValueMarks) (string, bool) {
+					if _, ok := marks["sensitive"]; ok {
+						return "(sensitive value)", true
+					}
+					return "", false
+				},
+			},
+			Want: `{
+  password = (sensitive value)
+  user     = "admin"
+}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := FormatValue(test.Value, test.Opts)
+			if got != test.Want {
+				t.Errorf("wrong result\ngot:\n%s\nwant:\n%s", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestFormatValueUnsortableSet(t *testing.T) {
+	capsuleTy := cty.CapsuleWithOps("unsortable", reflect.TypeOf(0), &cty.CapsuleOps{
+		GoString: func(v any) string {
+			return fmt.Sprintf("unsortable(%d)", *(v.(*int)))
+		},
+	})
+	a, b, c := 1, 2, 3
+	val := cty.SetVal([]cty.Value{
+		cty.CapsuleVal(capsuleTy, &a),
+		cty.CapsuleVal(capsuleTy, &b),
+		cty.CapsuleVal(capsuleTy, &c),
+	})
+
+	// Capsule values without a Compare operation can't be sorted, so they
+	// are rendered in the set's internal order even when SortSets is set.
+	got := FormatValue(val, Options{SortSets: true})
+	want := FormatValue(val, Options{})
+	if got != want {
+		t.Errorf("wrong result\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,303 @@
+package cty
+
+import (
+	"sort"
+)
+
+// ChangeKind describes the kind of a Change returned by Diff.
+type ChangeKind rune
+
+const (
+	// ChangeAdd represents a value that is present only in the new value,
+	// such as a new map element or an element appended to a list.
+	ChangeAdd ChangeKind = '+'
+
+	// ChangeRemove represents a value that is present only in the old value.
+	ChangeRemove ChangeKind = '-'
+
+	// ChangeUpdate represents a location where both values are present but
+	// differ.
+	ChangeUpdate ChangeKind = '~'
+)
+
+// Change is a single difference between two values, as returned by Diff.
+type Change struct {
+	// Path is the location of the change. For ChangeRemove this is the path
+	// through the old value, while for ChangeAdd and ChangeUpdate it is the
+	// path through the new value. These differ only when list elements are
+	// matched by key, in which case an element's index may have changed.
+	Path Path
+
+	Kind ChangeKind
+
+	// Old and New are the values before and after the change, including any
+	// marks they had. Old is NilVal for ChangeAdd and New is NilVal for
+	// ChangeRemove.
+	Old, New Value
+}
+
+// DiffOptions customizes the behavior of DiffWithOptions.
+type DiffOptions struct {
+	// ListKey, if set, is called for each element of any list being compared
+	// to obtain a key that identifies it. Elements in the old and new list
+	// that have the same key are compared with each other regardless of their
+	// indices, and any others are reported as removed or added.
+	//
+	// The path argument is the path of the list itself, so that a caller can
+	// use a different key for each list in a data structure. The path may not
+	// be used after ListKey returns, since its backing array is re-used for
+	// other calls. The element has any marks of the list itself in addition
+	// to its own. ListKey should return false as its second result to
+	// indicate that the element has no key, in which case it will always be
+	// reported as either removed or added. If more than one element in the
+	// same list has the same key then only the first is considered to have
+	// that key.
+	//
+	// If ListKey is nil, list elements are compared by index.
+	ListKey func(path Path, elem Value) (string, bool)
+}
+
+// Diff compares the two given values and returns a description of how to
+// get from a to b as a sequence of changes at different paths.
+//
+// Collections and structural values of the same type are compared element
+// by element, recursively. Map elements are matched by key and list and tuple
+// elements by index, while set elements are matched by identity, so that a
+// changed set element is reported as the removal of the old element and the
+// addition of the new one. The path of a set element uses the element itself
+// as the key of an IndexStep, following the usual convention for paths
+// through sets.
+//
+// Unknown values, null values, and values of different types can't be
+// compared element by element, so a difference involving any of those is
+// reported as a single update of the whole value. Values whose marks differ
+// are also reported as a single update of the whole value, even if their
+// unmarked values are equal.
+//
+// Changes are returned in a deterministic order, with object attributes and
+// map keys in lexical order and list and tuple elements in index order. The
+// result is empty if the two values are equal, as defined by Value.RawEquals.
+func Diff(a, b Value) []Change {
+	return DiffWithOptions(a, b, DiffOptions{})
+}
+
+// DiffWithOptions is like Diff but allows customizing the comparison with
+// the given options.
+func DiffWithOptions(a, b Value, opts DiffOptions) []Change {
+	d := &differ{opts: opts}
+	d.diff(nil, a, b)
+	return d.changes
+}
+
+type differ struct {
+	opts    DiffOptions
+	changes []Change
+}
+
+func (d *differ) add(path Path, kind ChangeKind, old, new Value) {
+	d.changes = append(d.changes, Change{
+		Path: path.Copy(),
+		Kind: kind,
+		Old:  old,
+		New:  new,
+	})
+}
+
+func (d *differ) diff(path Path, a, b Value) {
+	if a.RawEquals(b) {
+		return
+	}
+
+	ty := a.Type()
+	switch {
+	case !ty.Equals(b.Type()),
+		!a.HasSameMarks(b),
+		!a.IsKnown() || !b.IsKnown(),
+		a.IsNull() || b.IsNull(),
+		ty.IsPrimitiveType(),
+		ty.IsCapsuleType():
+		d.add(path, ChangeUpdate, a, b)
+		return
+	}
+
+	// a and b have the same marks, so we can unmark both and then apply the
+	// marks to each of the nested values we visit, so that they appear in
+	// any changes we report.
+	ua, marks := a.Unmark()
+	ub, _ := b.Unmark()
+	switch {
+	case ty.IsObjectType():
+		attrs := ty.AttributeTypes()
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			d.diff(append(path, GetAttrStep{Name: name}), ua.GetAttr(name).WithMarks(marks), ub.GetAttr(name).WithMarks(marks))
+		}
+	case ty.IsTupleType():
+		for i := range ty.TupleElementTypes() {
+			idx := NumberIntVal(int64(i))
+			d.diff(append(path, IndexStep{Key: idx}), ua.Index(idx).WithMarks(marks), ub.Index(idx).WithMarks(marks))
+		}
+	case ty.IsMapType():
+		am := markedValueMap(ua, marks)
+		bm := markedValueMap(ub, marks)
+		keys := make([]string, 0, len(am)+len(bm))
+		for k := range am {
+			keys = append(keys, k)
+		}
+		for k := range bm {
+			if _, exists := am[k]; !exists {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			av, aok := am[k]
+			bv, bok := bm[k]
+			elemPath := append(path, IndexStep{Key: StringVal(k)})
+			switch {
+			case !bok:
+				d.add(elemPath, ChangeRemove, av, NilVal)
+			case !aok:
+				d.add(elemPath, ChangeAdd, NilVal, bv)
+			default:
+				d.diff(elemPath, av, bv)
+			}
+		}
+	case ty.IsListType():
+		as := markedValueSlice(ua, marks)
+		bs := markedValueSlice(ub, marks)
+		if d.opts.ListKey != nil {
+			d.diffListByKey(path, as, bs)
+			return
+		}
+		for i := 0; i < len(as) || i < len(bs); i++ {
+			elemPath := append(path, IndexStep{Key: NumberIntVal(int64(i))})
+			switch {
+			case i >= len(bs):
+				d.add(elemPath, ChangeRemove, as[i], NilVal)
+			case i >= len(as):
+				d.add(elemPath, ChangeAdd, NilVal, bs[i])
+			default:
+				d.diff(elemPath, as[i], bs[i])
+			}
+		}
+	case ty.IsSetType():
+		// Set elements can't have marks of their own, so the keys in the
+		// paths are the unmarked elements even though the reported values
+		// carry the marks of the set.
+		as := ua.AsValueSlice()
+		bs := ub.AsValueSlice()
+		for _, av := range as {
+			if !containsRawEqual(bs, av) {
+				d.add(append(path, IndexStep{Key: av}), ChangeRemove, av.WithMarks(marks), NilVal)
+			}
+		}
+		for _, bv := range bs {
+			if !containsRawEqual(as, bv) {
+				d.add(append(path, IndexStep{Key: bv}), ChangeAdd, NilVal, bv.WithMarks(marks))
+			}
+		}
+	default:
+		// Should never happen, since above should be exhaustive
+		panic("Diff does not support the given type")
+	}
+}
+
+func (d *differ) diffListByKey(path Path, as, bs []Value) {
+	type keyedElem struct {
+		idx     int
+		matched bool
+	}
+	aKeys := make(map[string]*keyedElem, len(as))
+	aKeyOf := make([]string, len(as))
+	aHasKey := make([]bool, len(as))
+	for i, av := range as {
+		k, ok := d.opts.ListKey(path, av)
+		if !ok {
+			continue
+		}
+		if _, exists := aKeys[k]; exists {
+			continue
+		}
+		aKeys[k] = &keyedElem{idx: i}
+		aKeyOf[i] = k
+		aHasKey[i] = true
+	}
+
+	// We visit the new elements first so that we can find out which of the
+	// old elements were matched, but we report the removals of unmatched
+	// old elements before the changes to the new elements.
+	var removes, others []Change
+	saved := d.changes
+	d.changes = nil
+	bSeen := make(map[string]struct{}, len(bs))
+	for i, bv := range bs {
+		elemPath := append(path, IndexStep{Key: NumberIntVal(int64(i))})
+		k, ok := d.opts.ListKey(path, bv)
+		if ok {
+			if _, dupe := bSeen[k]; dupe {
+				ok = false
+			}
+			bSeen[k] = struct{}{}
+		}
+		if ok {
+			if match, exists := aKeys[k]; exists {
+				match.matched = true
+				d.diff(elemPath, as[match.idx], bv)
+				continue
+			}
+		}
+		d.add(elemPath, ChangeAdd, NilVal, bv)
+	}
+	others = d.changes
+	d.changes = nil
+
+	for i, av := range as {
+		if aHasKey[i] && aKeys[aKeyOf[i]].matched {
+			continue
+		}
+		elemPath := append(path, IndexStep{Key: NumberIntVal(int64(i))})
+		d.add(elemPath, ChangeRemove, av, NilVal)
+	}
+	removes = d.changes
+
+	d.changes = append(saved, removes...)
+	d.changes = append(d.changes, others...)
+}
+
+// markedValueMap is like Value.AsValueMap except that the given marks are
+// applied to each of the elements.
+func markedValueMap(v Value, marks ValueMarks) map[string]Value {
+	ret := v.AsValueMap()
+	if len(marks) != 0 {
+		for k, ev := range ret {
+			ret[k] = ev.WithMarks(marks)
+		}
+	}
+	return ret
+}
+
+// markedValueSlice is like Value.AsValueSlice except that the given marks
+// are applied to each of the elements.
+func markedValueSlice(v Value, marks ValueMarks) []Value {
+	ret := v.AsValueSlice()
+	if len(marks) != 0 {
+		for i, ev := range ret {
+			ret[i] = ev.WithMarks(marks)
+		}
+	}
+	return ret
+}
+
+func containsRawEqual(vals []Value, v Value) bool {
+	for _, candidate := range vals {
+		if candidate.RawEquals(v) {
+			return true
+		}
+	}
+	return false
+}
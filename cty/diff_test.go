@@ -0,0 +1,217 @@
+package cty_test
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestDiff(t *testing.T) {
+	tests := map[string]struct {
+		A, B cty.Value
+		Opts cty.DiffOptions
+		Want []cty.Change
+	}{
+		"equal": {
+			cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("a")}),
+			cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("a")}),
+			cty.DiffOptions{},
+			nil,
+		},
+		"primitive": {
+			cty.StringVal("a"),
+			cty.StringVal("b"),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.Path{}, Kind: cty.ChangeUpdate, Old: cty.StringVal("a"), New: cty.StringVal("b")},
+			},
+		},
+		"different types": {
+			cty.StringVal("1"),
+			cty.NumberIntVal(1),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.Path{}, Kind: cty.ChangeUpdate, Old: cty.StringVal("1"), New: cty.NumberIntVal(1)},
+			},
+		},
+		"nested object attributes": {
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+				"b": cty.ObjectVal(map[string]cty.Value{
+					"c": cty.NullVal(cty.String),
+					"d": cty.True,
+				}),
+			}),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+				"b": cty.ObjectVal(map[string]cty.Value{
+					"c": cty.StringVal("c"),
+					"d": cty.UnknownVal(cty.Bool),
+				}),
+			}),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.GetAttrPath("b").GetAttr("c"), Kind: cty.ChangeUpdate, Old: cty.NullVal(cty.String), New: cty.StringVal("c")},
+				{Path: cty.GetAttrPath("b").GetAttr("d"), Kind: cty.ChangeUpdate, Old: cty.True, New: cty.UnknownVal(cty.Bool)},
+			},
+		},
+		"map elements": {
+			cty.MapVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+				"b": cty.StringVal("b"),
+			}),
+			cty.MapVal(map[string]cty.Value{
+				"b": cty.StringVal("B"),
+				"c": cty.StringVal("c"),
+			}),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.IndexStringPath("a"), Kind: cty.ChangeRemove, Old: cty.StringVal("a"), New: cty.NilVal},
+				{Path: cty.IndexStringPath("b"), Kind: cty.ChangeUpdate, Old: cty.StringVal("b"), New: cty.StringVal("B")},
+				{Path: cty.IndexStringPath("c"), Kind: cty.ChangeAdd, Old: cty.NilVal, New: cty.StringVal("c")},
+			},
+		},
+		"list by index": {
+			cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b"), cty.StringVal("c")}),
+			cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("c")}),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.IndexIntPath(1), Kind: cty.ChangeUpdate, Old: cty.StringVal("b"), New: cty.StringVal("c")},
+				{Path: cty.IndexIntPath(2), Kind: cty.ChangeRemove, Old: cty.StringVal("c"), New: cty.NilVal},
+			},
+		},
+		"list by key": {
+			cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("a"), "v": cty.NumberIntVal(1)}),
+				cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("b"), "v": cty.NumberIntVal(2)}),
+				cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("c"), "v": cty.NumberIntVal(3)}),
+			}),
+			cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("a"), "v": cty.NumberIntVal(1)}),
+				cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("c"), "v": cty.NumberIntVal(4)}),
+				cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("d"), "v": cty.NumberIntVal(5)}),
+			}),
+			cty.DiffOptions{
+				ListKey: func(path cty.Path, elem cty.Value) (string, bool) {
+					return elem.GetAttr("id").AsString(), true
+				},
+			},
+			[]cty.Change{
+				{
+					Path: cty.IndexIntPath(1),
+					Kind: cty.ChangeRemove,
+					Old:  cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("b"), "v": cty.NumberIntVal(2)}),
+					New:  cty.NilVal,
+				},
+				{
+					Path: cty.IndexIntPath(1).GetAttr("v"),
+					Kind: cty.ChangeUpdate,
+					Old:  cty.NumberIntVal(3),
+					New:  cty.NumberIntVal(4),
+				},
+				{
+					Path: cty.IndexIntPath(2),
+					Kind: cty.ChangeAdd,
+					Old:  cty.NilVal,
+					New:  cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("d"), "v": cty.NumberIntVal(5)}),
+				},
+			},
+		},
+		"set elements": {
+			cty.SetVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			cty.SetVal([]cty.Value{cty.StringVal("b"), cty.StringVal("c")}),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.IndexStringPath("a"), Kind: cty.ChangeRemove, Old: cty.StringVal("a"), New: cty.NilVal},
+				{Path: cty.IndexStringPath("c"), Kind: cty.ChangeAdd, Old: cty.NilVal, New: cty.StringVal("c")},
+			},
+		},
+		"unknown collection": {
+			cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			cty.UnknownVal(cty.List(cty.String)),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.Path{}, Kind: cty.ChangeUpdate, Old: cty.ListVal([]cty.Value{cty.StringVal("a")}), New: cty.UnknownVal(cty.List(cty.String))},
+			},
+		},
+		"marks only": {
+			cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b").Mark("sensitive")}),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.IndexIntPath(1), Kind: cty.ChangeUpdate, Old: cty.StringVal("b"), New: cty.StringVal("b").Mark("sensitive")},
+			},
+		},
+		"marked containers": {
+			cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}).Mark("m"),
+			cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("c")}).Mark("m"),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.IndexIntPath(1), Kind: cty.ChangeUpdate, Old: cty.StringVal("b").Mark("m"), New: cty.StringVal("c").Mark("m")},
+			},
+		},
+		"marked object": {
+			cty.ObjectVal(map[string]cty.Value{"pw": cty.StringVal("old")}).Mark("sensitive"),
+			cty.ObjectVal(map[string]cty.Value{"pw": cty.StringVal("new")}).Mark("sensitive"),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.GetAttrPath("pw"), Kind: cty.ChangeUpdate, Old: cty.StringVal("old").Mark("sensitive"), New: cty.StringVal("new").Mark("sensitive")},
+			},
+		},
+		"marked nested containers": {
+			cty.ObjectVal(map[string]cty.Value{
+				"m": cty.MapVal(map[string]cty.Value{"a": cty.StringVal("a"), "b": cty.StringVal("b")}),
+				"l": cty.ListVal([]cty.Value{cty.StringVal("a").Mark("x")}),
+				"s": cty.SetVal([]cty.Value{cty.StringVal("a")}),
+			}).Mark("sensitive"),
+			cty.ObjectVal(map[string]cty.Value{
+				"m": cty.MapVal(map[string]cty.Value{"a": cty.StringVal("c"), "d": cty.StringVal("d")}),
+				"l": cty.ListVal([]cty.Value{cty.StringVal("a").Mark("x"), cty.StringVal("b").Mark("x")}),
+				"s": cty.SetVal([]cty.Value{cty.StringVal("b")}),
+			}).Mark("sensitive"),
+			cty.DiffOptions{},
+			[]cty.Change{
+				{Path: cty.GetAttrPath("l").IndexInt(1), Kind: cty.ChangeAdd, Old: cty.NilVal, New: cty.StringVal("b").WithMarks(cty.NewValueMarks("x", "sensitive"))},
+				{Path: cty.GetAttrPath("m").IndexString("a"), Kind: cty.ChangeUpdate, Old: cty.StringVal("a").Mark("sensitive"), New: cty.StringVal("c").Mark("sensitive")},
+				{Path: cty.GetAttrPath("m").IndexString("b"), Kind: cty.ChangeRemove, Old: cty.StringVal("b").Mark("sensitive"), New: cty.NilVal},
+				{Path: cty.GetAttrPath("m").IndexString("d"), Kind: cty.ChangeAdd, Old: cty.NilVal, New: cty.StringVal("d").Mark("sensitive")},
+				{Path: cty.GetAttrPath("s").Index(cty.StringVal("a")), Kind: cty.ChangeRemove, Old: cty.StringVal("a").Mark("sensitive"), New: cty.NilVal},
+				{Path: cty.GetAttrPath("s").Index(cty.StringVal("b")), Kind: cty.ChangeAdd, Old: cty.NilVal, New: cty.StringVal("b").Mark("sensitive")},
+			},
+		},
+		"marked list by key": {
+			cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("a"), "v": cty.StringVal("1")}),
+			}).Mark("sensitive"),
+			cty.ListVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("b"), "v": cty.StringVal("3")}),
+				cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("a"), "v": cty.StringVal("2")}),
+			}).Mark("sensitive"),
+			cty.DiffOptions{
+				ListKey: func(path cty.Path, elem cty.Value) (string, bool) {
+					id, _ := elem.GetAttr("id").Unmark()
+					return id.AsString(), true
+				},
+			},
+			[]cty.Change{
+				{Path: cty.IndexIntPath(0), Kind: cty.ChangeAdd, Old: cty.NilVal, New: cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("b"), "v": cty.StringVal("3")}).Mark("sensitive")},
+				{Path: cty.IndexIntPath(1).GetAttr("v"), Kind: cty.ChangeUpdate, Old: cty.StringVal("1").Mark("sensitive"), New: cty.StringVal("2").Mark("sensitive")},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := cty.DiffWithOptions(test.A, test.B, test.Opts)
+			if len(got) != len(test.Want) {
+				t.Fatalf("wrong number of changes\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+			for i := range got {
+				g, w := got[i], test.Want[i]
+				if !g.Path.Equals(w.Path) || g.Kind != w.Kind || !g.Old.RawEquals(w.Old) || !g.New.RawEquals(w.New) {
+					t.Errorf("wrong change %d\ngot:  %#v\nwant: %#v", i, g, w)
+				}
+			}
+		})
+	}
+}
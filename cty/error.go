@@ -11,6 +11,12 @@ type PathError struct {
 	Path Path
 }
 
+// Unwrap returns the error that the PathError wraps, so that functions like
+// errors.As can find a more specific error describing the problem.
+func (e PathError) Unwrap() error {
+	return e.error
+}
+
 func errorf(path Path, f string, args ...any) error {
 	// We need to copy the Path because often our caller builds it by
 	// continually mutating the same underlying buffer.
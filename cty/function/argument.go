@@ -1,6 +1,8 @@
 package function
 
 import (
+	"fmt"
+
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -13,6 +15,27 @@ type Parameter struct {
 	// Description is an optional description for the argument.
 	Description string
 
+	// Deprecated, if set, indicates that the parameter is deprecated and
+	// describes how callers should stop using it. Function.CallWithWarnings
+	// returns a warning for each non-null argument given for a deprecated
+	// parameter.
+	Deprecated *Deprecation
+
+	// If Optional is set then callers may omit the argument for this
+	// parameter, in which case Default is passed in its place to both the
+	// type-check function and the implementation function. Only the last
+	// parameters in Spec.Params may be optional, and if the function also
+	// has a VarParam then variadic arguments may be given only after all of
+	// the optional arguments.
+	Optional bool
+
+	// Default is the value used in place of an omitted optional argument,
+	// which must conform to Type. If Default is cty.NilVal then a null value
+	// of Type is used instead, which requires AllowNull to also be set.
+	//
+	// Default is ignored for parameters that are not optional.
+	Default cty.Value
+
 	// A type that any argument for this parameter must conform to.
 	// cty.DynamicPseudoType can be used, either at top-level or nested
 	// in a parameterized type, to indicate that any type should be
@@ -71,3 +94,47 @@ type Parameter struct {
 	// and ignoring the value itself.
 	AllowMarked bool
 }
+
+// defaultValue returns the value to use in place of an omitted argument for
+// an optional parameter.
+func (p *Parameter) defaultValue() cty.Value {
+	if p.Default == cty.NilVal {
+		return cty.NullVal(p.Type)
+	}
+	return p.Default
+}
+
+// copy returns a copy of the parameter whose Deprecated, if any, is also a
+// copy, so that callers can't modify the function's own deprecation.
+func (p *Parameter) copy() Parameter {
+	ret := *p
+	if ret.Deprecated != nil {
+		ret.Deprecated = ret.Deprecated.copy()
+	}
+	return ret
+}
+
+// assertValidOptionalParams panics if the given positional parameters have a
+// required parameter after an optional one, or if any optional parameter has
+// an invalid default value.
+func assertValidOptionalParams(params []Parameter) {
+	optional := false
+	for i := range params {
+		p := &params[i]
+		if !p.Optional {
+			if optional {
+				panic(fmt.Sprintf("required parameter %d follows an optional parameter", i))
+			}
+			continue
+		}
+		optional = true
+
+		def := p.defaultValue()
+		if def.IsNull() && !p.AllowNull {
+			panic(fmt.Sprintf("optional parameter %d has a null default but does not allow null", i))
+		}
+		if errs := def.Type().TestConformance(p.Type); errs != nil {
+			panic(fmt.Sprintf("default for optional parameter %d is invalid: %s", i, errs[0]))
+		}
+	}
+}
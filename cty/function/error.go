@@ -29,6 +29,30 @@ func NewArgError(i int, err error) error {
 	}
 }
 
+// NamedArgError represents an error with one of the arguments in a call made
+// using Function.CallNamed. The attribute Name is the name of the argument in
+// question, or an empty string if the problem is that the corresponding
+// parameter has no name. Index is the zero-based index of the corresponding
+// parameter, or -1 if the function has no parameter with that name.
+//
+// As with ArgError, its error *may* be a cty.PathError.
+type NamedArgError struct {
+	error
+	Name  string
+	Index int
+}
+
+// NewNamedArgErrorf returns a NamedArgError for the argument with the given
+// name and parameter index, with an error produced by passing the given
+// format and arguments to fmt.Errorf.
+func NewNamedArgErrorf(name string, i int, f string, args ...any) error {
+	return NamedArgError{
+		error: fmt.Errorf(f, args...),
+		Name:  name,
+		Index: i,
+	}
+}
+
 // PanicError indicates that a panic occurred while executing either a
 // function's type or implementation function. This is captured and wrapped
 // into a normal error so that callers (expected to be language runtimes)
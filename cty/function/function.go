@@ -17,10 +17,21 @@ type Spec struct {
 	// Description is an optional description for the function specification.
 	Description string
 
+	// Deprecated, if set, indicates that the function is deprecated and
+	// describes how callers should stop using it. CallWithWarnings returns a
+	// warning for each call to a deprecated function.
+	Deprecated *Deprecation
+
 	// Params is a description of the positional parameters for the function.
 	// The standard checking logic rejects any calls that do not provide
 	// arguments conforming to this definition, freeing the function
 	// implementer from dealing with such inconsistencies.
+	//
+	// Any optional parameters must come after all of the required ones.
+	// Callers may omit the arguments for optional parameters, but the type
+	// and implementation functions always receive a value for every
+	// parameter, with the parameter's default standing in for any that were
+	// omitted.
 	Params []Parameter
 
 	// VarParam is an optional specification of additional "varargs" the
@@ -60,13 +71,22 @@ type Spec struct {
 	// If a TypeFunc is also provided, the value returned from Impl *must*
 	// conform to the type it returns, or a call to the function will panic.
 	Impl ImplFunc
+
+	// ImplWithWarnings is an alternative to Impl for functions that may
+	// return warnings alongside a successful result. If it is set then Impl
+	// is ignored.
+	ImplWithWarnings ImplWithWarningsFunc
 }
 
 // New creates a new function with the given specification.
 //
 // After passing a Spec to this function, the caller must no longer read from
 // or mutate it.
+//
+// New panics if the spec has a required parameter after an optional one, or
+// an optional parameter whose default value does not conform to its type.
 func New(spec *Spec) Function {
+	assertValidOptionalParams(spec.Params)
 	f := Function{
 		spec: spec,
 	}
@@ -97,6 +117,14 @@ type TypeFunc func(args []cty.Value) (cty.Type, error)
 // functions whose return type is a function of the arguments.
 type ImplFunc func(args []cty.Value, retType cty.Type) (cty.Value, error)
 
+// ImplWithWarningsFunc is like ImplFunc except that the implementation may
+// also return warnings about the call, which don't prevent it from succeeding.
+//
+// Warnings are returned to callers of Function.CallWithWarnings, but are
+// discarded by Function.Call. Any warnings returned along with an error are
+// discarded too.
+type ImplWithWarningsFunc func(args []cty.Value, retType cty.Type) (cty.Value, []Warning, error)
+
 // StaticReturnType returns a TypeFunc that always returns the given type.
 //
 // This is provided as a convenience for defining a function whose return
@@ -109,6 +137,8 @@ func StaticReturnType(ty cty.Type) TypeFunc {
 
 // ReturnType returns the return type of a function given a set of candidate
 // argument types, or returns an error if the given types are unacceptable.
+// Types for optional parameters may be omitted, in which case their default
+// values are used.
 //
 // If the caller already knows values for at least some of the arguments
 // it can be better to call ReturnTypeForValues, since certain functions may
@@ -126,8 +156,17 @@ func (f Function) returnTypeForValues(args []cty.Value) (ty cty.Type, dynTypedAr
 	var posArgs []cty.Value
 	var varArgs []cty.Value
 
+	args = f.withDefaults(args)
+	required := f.requiredParams()
+
 	if f.spec.VarParam == nil {
 		if len(args) != len(f.spec.Params) {
+			if required != len(f.spec.Params) {
+				return cty.Type{}, false, fmt.Errorf(
+					"wrong number of arguments (%d to %d required; %d given)",
+					required, len(f.spec.Params), len(args),
+				)
+			}
 			return cty.Type{}, false, fmt.Errorf(
 				"wrong number of arguments (%d required; %d given)",
 				len(f.spec.Params), len(args),
@@ -140,7 +179,7 @@ func (f Function) returnTypeForValues(args []cty.Value) (ty cty.Type, dynTypedAr
 		if len(args) < len(f.spec.Params) {
 			return cty.Type{}, false, fmt.Errorf(
 				"wrong number of arguments (at least %d required; %d given)",
-				len(f.spec.Params), len(args),
+				required, len(args),
 			)
 		}
 
@@ -231,6 +270,33 @@ func (f Function) returnTypeForValues(args []cty.Value) (ty cty.Type, dynTypedAr
 	return ty, false, err
 }
 
+// withDefaults returns the given arguments with the default values of any
+// omitted optional parameters appended, or returns the arguments unchanged if
+// none were omitted or if some required arguments are also missing.
+func (f Function) withDefaults(args []cty.Value) []cty.Value {
+	params := f.spec.Params
+	if len(args) >= len(params) || len(args) < f.requiredParams() {
+		return args
+	}
+	ret := make([]cty.Value, len(params))
+	copy(ret, args)
+	for i := len(args); i < len(params); i++ {
+		ret[i] = params[i].defaultValue()
+	}
+	return ret
+}
+
+// requiredParams returns the number of positional parameters that are not
+// optional, which are always the first parameters.
+func (f Function) requiredParams() int {
+	for i, p := range f.spec.Params {
+		if p.Optional {
+			return i
+		}
+	}
+	return len(f.spec.Params)
+}
+
 // ReturnTypeForValues is similar to ReturnType but can be used if the caller
 // already knows the values of some or all of the arguments, in which case
 // the function may be able to determine a more definite result if its
@@ -246,11 +312,40 @@ func (f Function) ReturnTypeForValues(args []cty.Value) (ty cty.Type, err error)
 // Call actually calls the function with the given arguments, which must
 // conform to the function's parameter specification or an error will be
 // returned.
+//
+// Arguments for optional parameters may be omitted, in which case their
+// default values are passed to the implementation instead.
+//
+// Call discards any warnings about the call. Use CallWithWarnings to also
+// receive the warnings.
 func (f Function) Call(args []cty.Value) (val cty.Value, err error) {
+	val, _, err = f.CallWithWarnings(args)
+	return val, err
+}
+
+// CallWithWarnings is like Call except that it also returns any warnings
+// about a successful call, which the caller may wish to report to the user
+// without treating the call as failed.
+//
+// The warnings include one for calling a function that is deprecated and one
+// for each non-null argument given for a deprecated parameter, followed by
+// any warnings returned by a function implemented using ImplWithWarnings.
+func (f Function) CallWithWarnings(args []cty.Value) (val cty.Value, warnings []Warning, err error) {
+	// Only the arguments the caller actually gave can be deprecated, so we
+	// check for deprecations before filling in any default arguments.
+	return f.call(args, f.deprecationWarnings(args))
+}
+
+// call is the common implementation of CallWithWarnings and
+// CallNamedWithWarnings, which returns the given deprecation warnings
+// followed by any warnings from the implementation if the call succeeds.
+func (f Function) call(args []cty.Value, deprecations []Warning) (val cty.Value, warnings []Warning, err error) {
 	expectedType, dynTypeArgs, err := f.returnTypeForValues(args)
 	if err != nil {
-		return cty.NilVal, err
+		return cty.NilVal, nil, err
 	}
+	warnings = deprecations
+	args = f.withDefaults(args)
 
 	var resultMarks []cty.ValueMarks
 	// If we are returning an unknown early due to some unknown in the
@@ -336,7 +431,7 @@ func (f Function) Call(args []cty.Value) (val cty.Value, err error) {
 	}
 
 	if returnUnknown {
-		return cty.UnknownVal(expectedType).WithMarks(resultMarks...), nil
+		return cty.UnknownVal(expectedType).WithMarks(resultMarks...), warnings, nil
 	}
 
 	var retVal cty.Value
@@ -346,13 +441,20 @@ func (f Function) Call(args []cty.Value) (val cty.Value, err error) {
 		defer func() {
 			if r := recover(); r != nil {
 				val = cty.NilVal
+				warnings = nil
 				err = errorForPanic(r)
 			}
 		}()
 
-		retVal, err = f.spec.Impl(args, expectedType)
+		if f.spec.ImplWithWarnings != nil {
+			var implWarnings []Warning
+			retVal, implWarnings, err = f.spec.ImplWithWarnings(args, expectedType)
+			warnings = append(warnings, implWarnings...)
+		} else {
+			retVal, err = f.spec.Impl(args, expectedType)
+		}
 		if err != nil {
-			return cty.NilVal, err
+			return cty.NilVal, nil, err
 		}
 		if len(resultMarks) > 0 {
 			retVal = retVal.WithMarks(resultMarks...)
@@ -368,7 +470,7 @@ func (f Function) Call(args []cty.Value) (val cty.Value, err error) {
 		))
 	}
 
-	return retVal, nil
+	return retVal, warnings, nil
 }
 
 // ProxyFunc the type returned by the method Function.Proxy.
@@ -386,9 +488,14 @@ func (f Function) Proxy() ProxyFunc {
 // Params returns information about the function's fixed positional parameters.
 // This does not include information about any variadic arguments accepted;
 // for that, call VarParam.
+//
+// Parameters whose Optional field is set may be omitted by callers, in which
+// case their Default is used instead.
 func (f Function) Params() []Parameter {
 	new := make([]Parameter, len(f.spec.Params))
-	copy(new, f.spec.Params)
+	for i, p := range f.spec.Params {
+		new[i] = p.copy()
+	}
 	return new
 }
 
@@ -399,7 +506,7 @@ func (f Function) VarParam() *Parameter {
 		return nil
 	}
 
-	ret := *f.spec.VarParam
+	ret := f.spec.VarParam.copy()
 	return &ret
 }
 
@@ -408,6 +515,15 @@ func (f Function) Description() string {
 	return f.spec.Description
 }
 
+// Deprecated returns information about why the function is deprecated, or
+// nil if the function is not deprecated.
+func (f Function) Deprecated() *Deprecation {
+	if f.spec.Deprecated == nil {
+		return nil
+	}
+	return f.spec.Deprecated.copy()
+}
+
 // WithNewDescriptions returns a new function that has the same signature
 // and implementation as the receiver but has the function description and
 // the parameter descriptions replaced with those given in the arguments.
@@ -0,0 +1,104 @@
+package function
+
+import (
+	"sort"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// CallNamed is like Call except that the arguments are given by the names of
+// their parameters, rather than by position. Arguments for optional
+// parameters may be omitted, in which case their default values are used,
+// but all of the required parameters must have arguments.
+//
+// Only the function's fixed parameters can be given by name, so a variadic
+// function called this way receives no variadic arguments. A function can be
+// called this way only if all of its fixed parameters have distinct names.
+//
+// Once the arguments are bound to parameters, CallNamed handles null,
+// unknown, marked, and dynamically-typed arguments in the same way as Call.
+// Any problems with individual arguments are returned as NamedArgError
+// values, but errors returned by the implementation function are returned
+// unchanged unless they are ArgError values describing one of the function's
+// own parameters.
+func (f Function) CallNamed(args map[string]cty.Value) (val cty.Value, err error) {
+	val, _, err = f.CallNamedWithWarnings(args)
+	return val, err
+}
+
+// CallNamedWithWarnings is like CallNamed except that it also returns any
+// warnings about a successful call, as with CallWithWarnings. The Index of
+// each warning refers to the parameter that the argument was bound to.
+func (f Function) CallNamedWithWarnings(args map[string]cty.Value) (val cty.Value, warnings []Warning, err error) {
+	posArgs, given, err := f.bindNamedArgs(args)
+	if err != nil {
+		return cty.NilVal, nil, err
+	}
+
+	val, warnings, err = f.call(posArgs, f.deprecationWarnings(given))
+	// The index of an argument isn't meaningful to a caller that gave
+	// arguments by name, so we'll report the name instead. An ArgError that
+	// is wrapped in another error, or whose index is out of range, may have
+	// come from a call to some other function made by the implementation,
+	// so we leave those unchanged.
+	if argErr, ok := err.(ArgError); ok && argErr.Index >= 0 && argErr.Index < len(f.spec.Params) {
+		err = NamedArgError{
+			error: argErr.error,
+			Name:  f.spec.Params[argErr.Index].Name,
+			Index: argErr.Index,
+		}
+	}
+	return val, warnings, err
+}
+
+// bindNamedArgs returns positional arguments for the function's fixed
+// parameters with the values of the given named arguments, using default
+// values for any optional arguments that were omitted. It also returns only
+// the arguments the caller actually gave, with cty.NilVal in place of those
+// that were omitted.
+func (f Function) bindNamedArgs(args map[string]cty.Value) (posArgs, given []cty.Value, err error) {
+	params := f.spec.Params
+	indices := make(map[string]int, len(params))
+	for i, param := range params {
+		if param.Name == "" {
+			return nil, nil, NewNamedArgErrorf("", i, "parameter %d has no name, so the function cannot be called with named arguments", i+1)
+		}
+		if prev, exists := indices[param.Name]; exists {
+			return nil, nil, NewNamedArgErrorf(param.Name, i, "parameters %d and %d are both named %q, so the function cannot be called with named arguments", prev+1, i+1, param.Name)
+		}
+		indices[param.Name] = i
+	}
+
+	// We check the names in lexical order so that the error for a call with
+	// more than one incorrect name is consistent between calls.
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	given = make([]cty.Value, len(params))
+	for _, name := range names {
+		i, exists := indices[name]
+		if !exists {
+			if vp := f.spec.VarParam; vp != nil && vp.Name == name {
+				return nil, nil, NewNamedArgErrorf(name, -1, "the variadic argument %q cannot be given by name", name)
+			}
+			return nil, nil, NewNamedArgErrorf(name, -1, "there is no parameter named %q", name)
+		}
+		given[i] = args[name]
+	}
+
+	posArgs = make([]cty.Value, len(params))
+	for i, param := range params {
+		switch {
+		case given[i] != cty.NilVal:
+			posArgs[i] = given[i]
+		case param.Optional:
+			posArgs[i] = param.defaultValue()
+		default:
+			return nil, nil, NewNamedArgErrorf(param.Name, i, "missing required argument %q", param.Name)
+		}
+	}
+	return posArgs, given, nil
+}
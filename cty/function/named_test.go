@@ -0,0 +1,281 @@
+package function
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCallNamed(t *testing.T) {
+	tests := map[string]struct {
+		Args      map[string]cty.Value
+		Want      cty.Value
+		WantErr   string
+		WantName  string
+		WantIndex int
+	}{
+		"required only": {
+			Args: map[string]cty.Value{"str": cty.StringVal("a")},
+			Want: cty.StringVal("a"),
+		},
+		"all given": {
+			Args: map[string]cty.Value{
+				"suffix": cty.StringVal("b"),
+				"sep":    cty.StringVal("+"),
+				"str":    cty.StringVal("a"),
+			},
+			Want: cty.StringVal("a+b"),
+		},
+		"skipping an optional argument": {
+			Args: map[string]cty.Value{
+				"str":    cty.StringVal("a"),
+				"suffix": cty.StringVal("b"),
+			},
+			Want: cty.StringVal("a-b"),
+		},
+		"unknown argument": {
+			Args: map[string]cty.Value{
+				"str":    cty.StringVal("a"),
+				"suffix": cty.UnknownVal(cty.String),
+			},
+			Want: cty.UnknownVal(cty.String),
+		},
+		"dynamic argument": {
+			Args: map[string]cty.Value{
+				"str": cty.DynamicVal,
+			},
+			Want: cty.DynamicVal,
+		},
+		"marked argument": {
+			Args: map[string]cty.Value{
+				"str":    cty.StringVal("a").Mark("sensitive"),
+				"suffix": cty.StringVal("b"),
+			},
+			Want: cty.StringVal("a-b").Mark("sensitive"),
+		},
+		"null argument": {
+			Args: map[string]cty.Value{
+				"str":    cty.StringVal("a"),
+				"suffix": cty.NullVal(cty.String),
+			},
+			Want: cty.StringVal("a"),
+		},
+		"null for required argument": {
+			Args: map[string]cty.Value{
+				"str": cty.NullVal(cty.String),
+			},
+			WantErr:   "argument must not be null",
+			WantName:  "str",
+			WantIndex: 0,
+		},
+		"wrong type": {
+			Args: map[string]cty.Value{
+				"str": cty.StringVal("a"),
+				"sep": cty.EmptyObjectVal,
+			},
+			WantErr:   "string required, but received object",
+			WantName:  "sep",
+			WantIndex: 1,
+		},
+		"missing required argument": {
+			Args: map[string]cty.Value{
+				"sep": cty.StringVal("+"),
+			},
+			WantErr:   `missing required argument "str"`,
+			WantName:  "str",
+			WantIndex: 0,
+		},
+		"unknown names": {
+			Args: map[string]cty.Value{
+				"str":  cty.StringVal("a"),
+				"sepp": cty.StringVal("+"),
+				"zzz":  cty.StringVal("+"),
+			},
+			WantErr:   `there is no parameter named "sepp"`,
+			WantName:  "sepp",
+			WantIndex: -1,
+		},
+		"variadic argument": {
+			Args: map[string]cty.Value{
+				"str":  cty.StringVal("a"),
+				"more": cty.StringVal("b"),
+			},
+			WantErr:   `the variadic argument "more" cannot be given by name`,
+			WantName:  "more",
+			WantIndex: -1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := testOptionalFunc.CallNamed(test.Args)
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v", got)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				argErr, ok := err.(NamedArgError)
+				if !ok {
+					t.Fatalf("error is %T, not NamedArgError", err)
+				}
+				if argErr.Name != test.WantName || argErr.Index != test.WantIndex {
+					t.Errorf("wrong argument\ngot:  %q (%d)\nwant: %q (%d)", argErr.Name, argErr.Index, test.WantName, test.WantIndex)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.Want) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestCallNamedInvalidParams(t *testing.T) {
+	tests := map[string]struct {
+		Params  []Parameter
+		WantErr string
+	}{
+		"duplicate names": {
+			Params: []Parameter{
+				{Name: "a", Type: cty.String},
+				{Name: "a", Type: cty.String},
+			},
+			WantErr: `parameters 1 and 2 are both named "a", so the function cannot be called with named arguments`,
+		},
+		"unnamed parameter": {
+			Params: []Parameter{
+				{Name: "a", Type: cty.String},
+				{Type: cty.String},
+			},
+			WantErr: "parameter 2 has no name, so the function cannot be called with named arguments",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := New(&Spec{
+				Params: test.Params,
+				Type:   StaticReturnType(cty.String),
+				Impl:   stubImpl,
+			})
+			_, err := f.CallNamed(map[string]cty.Value{"a": cty.StringVal("")})
+			if err == nil {
+				t.Fatalf("unexpected success")
+			}
+			if got := err.Error(); got != test.WantErr {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+			}
+			argErr, ok := err.(NamedArgError)
+			if !ok {
+				t.Fatalf("error is %T, not NamedArgError", err)
+			}
+			if argErr.Index != 1 {
+				t.Errorf("wrong index %d; want 1", argErr.Index)
+			}
+		})
+	}
+}
+
+func TestCallNamedWithWarnings(t *testing.T) {
+	f := New(&Spec{
+		Params: []Parameter{
+			{Name: "a", Type: cty.String},
+			{
+				Name:       "b",
+				Type:       cty.String,
+				Optional:   true,
+				Default:    cty.StringVal("x"),
+				Deprecated: &Deprecation{Replacement: "c"},
+			},
+			{
+				Name:       "c",
+				Type:       cty.String,
+				Optional:   true,
+				Default:    cty.StringVal("y"),
+				Deprecated: &Deprecation{},
+			},
+		},
+		Type: StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.StringVal(args[0].AsString() + args[1].AsString() + args[2].AsString()), nil
+		},
+	})
+
+	got, warnings, err := f.CallNamedWithWarnings(map[string]cty.Value{
+		"a": cty.StringVal("a"),
+		"b": cty.StringVal("b"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := cty.StringVal("aby"); !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+	// Only the deprecated argument that was actually given is reported.
+	if len(warnings) != 1 {
+		t.Fatalf("wrong number of warnings %d; want 1\n%#v", len(warnings), warnings)
+	}
+	if got, want := warnings[0].Message, `the "b" argument is deprecated; use c instead`; got != want {
+		t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := warnings[0].Index, 1; got != want {
+		t.Errorf("wrong index %d; want %d", got, want)
+	}
+}
+
+func TestCallNamedForwardedArgError(t *testing.T) {
+	inner := New(&Spec{
+		Params: []Parameter{
+			{Name: "x", Type: cty.String},
+			{Name: "y", Type: cty.String},
+			{Name: "z", Type: cty.String},
+		},
+		Type: StaticReturnType(cty.String),
+		Impl: stubImpl,
+	})
+
+	tests := map[string]func(err error) error{
+		"unwrapped": func(err error) error {
+			return err
+		},
+		"wrapped": func(err error) error {
+			return fmt.Errorf("calling inner: %w", err)
+		},
+	}
+
+	for name, wrap := range tests {
+		t.Run(name, func(t *testing.T) {
+			outer := New(&Spec{
+				Params: []Parameter{
+					{Name: "a", Type: cty.String},
+				},
+				Type: StaticReturnType(cty.String),
+				Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+					// The null third argument makes inner fail with an
+					// ArgError whose index is out of range for outer.
+					_, err := inner.Call([]cty.Value{args[0], args[0], cty.NullVal(cty.String)})
+					return cty.NilVal, wrap(err)
+				},
+			})
+
+			_, err := outer.CallNamed(map[string]cty.Value{"a": cty.StringVal("a")})
+			if err == nil {
+				t.Fatalf("unexpected success")
+			}
+			if _, ok := err.(NamedArgError); ok {
+				t.Fatalf("error from the inner function was rewritten: %#v", err)
+			}
+			var argErr ArgError
+			if !errors.As(err, &argErr) || argErr.Index != 2 {
+				t.Errorf("error does not wrap the inner ArgError: %#v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package function
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// testOptionalFunc is a function that joins a required string with an
+// optional separator and an optional suffix, followed by any number of
+// additional strings.
+var testOptionalFunc = New(&Spec{
+	Params: []Parameter{
+		{
+			Name: "str",
+			Type: cty.String,
+		},
+		{
+			Name:     "sep",
+			Type:     cty.String,
+			Optional: true,
+			Default:  cty.StringVal("-"),
+		},
+		{
+			Name:      "suffix",
+			Type:      cty.String,
+			Optional:  true,
+			AllowNull: true,
+		},
+	},
+	VarParam: &Parameter{
+		Name: "more",
+		Type: cty.String,
+	},
+	Type: StaticReturnType(cty.String),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		sep := args[1].AsString()
+		ret := args[0].AsString()
+		if !args[2].IsNull() {
+			ret += sep + args[2].AsString()
+		}
+		for _, arg := range args[3:] {
+			ret += sep + arg.AsString()
+		}
+		return cty.StringVal(ret), nil
+	},
+})
+
+func TestCallOptionalParams(t *testing.T) {
+	tests := map[string]struct {
+		Args    []cty.Value
+		Want    cty.Value
+		WantErr string
+	}{
+		"all omitted": {
+			Args: []cty.Value{cty.StringVal("a")},
+			Want: cty.StringVal("a"),
+		},
+		"default with null default": {
+			Args: []cty.Value{cty.StringVal("a"), cty.StringVal("+")},
+			Want: cty.StringVal("a"),
+		},
+		"all given": {
+			Args: []cty.Value{cty.StringVal("a"), cty.StringVal("+"), cty.StringVal("b")},
+			Want: cty.StringVal("a+b"),
+		},
+		"with varargs": {
+			Args: []cty.Value{cty.StringVal("a"), cty.StringVal("+"), cty.StringVal("b"), cty.StringVal("c")},
+			Want: cty.StringVal("a+b+c"),
+		},
+		"unknown optional": {
+			Args: []cty.Value{cty.StringVal("a"), cty.UnknownVal(cty.String)},
+			Want: cty.UnknownVal(cty.String),
+		},
+		"marked optional": {
+			Args: []cty.Value{cty.StringVal("a"), cty.StringVal("+"), cty.StringVal("b").Mark("sensitive")},
+			Want: cty.StringVal("a+b").Mark("sensitive"),
+		},
+		"too few": {
+			Args:    []cty.Value{},
+			WantErr: "wrong number of arguments (at least 1 required; 0 given)",
+		},
+		"wrong type": {
+			Args:    []cty.Value{cty.StringVal("a"), cty.EmptyObjectVal},
+			WantErr: "string required, but received object",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := testOptionalFunc.Call(test.Args)
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v", got)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.Want) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestCallOptionalParamsNotVariadic(t *testing.T) {
+	var gotArgs []cty.Value
+	f := New(&Spec{
+		Params: []Parameter{
+			{Name: "a", Type: cty.Number},
+			{Name: "b", Type: cty.Number, Optional: true, Default: cty.NumberIntVal(2)},
+		},
+		Type: func(args []cty.Value) (cty.Type, error) {
+			gotArgs = args
+			return cty.Number, nil
+		},
+		Impl: stubImpl,
+	})
+
+	ty, err := f.ReturnType([]cty.Type{cty.Number})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ty != cty.Number {
+		t.Errorf("wrong return type %#v", ty)
+	}
+	wantArgs := []cty.Value{cty.UnknownVal(cty.Number), cty.NumberIntVal(2)}
+	if len(gotArgs) != len(wantArgs) || !gotArgs[0].RawEquals(wantArgs[0]) || !gotArgs[1].RawEquals(wantArgs[1]) {
+		t.Errorf("wrong arguments for type function\ngot:  %#v\nwant: %#v", gotArgs, wantArgs)
+	}
+
+	for _, args := range [][]cty.Value{{}, {cty.Zero, cty.Zero, cty.Zero}} {
+		_, err := f.Call(args)
+		want := fmt.Sprintf("wrong number of arguments (1 to 2 required; %d given)", len(args))
+		if err == nil || err.Error() != want {
+			t.Errorf("wrong error for %d arguments\ngot:  %v\nwant: %s", len(args), err, want)
+		}
+	}
+
+	if !f.Params()[1].Optional || f.Params()[0].Optional {
+		t.Errorf("wrong optional parameters in %#v", f.Params())
+	}
+}
+
+func TestCallOptionalParamsDeprecated(t *testing.T) {
+	f := New(&Spec{
+		Params: []Parameter{
+			{
+				Name:       "a",
+				Type:       cty.String,
+				Optional:   true,
+				Default:    cty.StringVal("x"),
+				Deprecated: &Deprecation{},
+			},
+		},
+		Type: StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return args[0], nil
+		},
+	})
+
+	// Using the default value of a deprecated parameter is not a use of
+	// the deprecated parameter.
+	got, warnings, err := f.CallWithWarnings(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := cty.StringVal("x"); !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("unexpected warnings: %#v", warnings)
+	}
+}
+
+func TestNewInvalidOptionalParams(t *testing.T) {
+	tests := map[string][]Parameter{
+		"required after optional": {
+			{Type: cty.String, Optional: true, Default: cty.StringVal("")},
+			{Type: cty.String},
+		},
+		"null default without AllowNull": {
+			{Type: cty.String, Optional: true},
+		},
+		"default of wrong type": {
+			{Type: cty.String, Optional: true, Default: cty.True},
+		},
+	}
+
+	for name, params := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("New did not panic")
+				}
+			}()
+			New(&Spec{
+				Params: params,
+				Type:   StaticReturnType(cty.String),
+				Impl:   stubImpl,
+			})
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package stdlib
+
+import (
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// Table returns a new function table containing every function in this
+// package, each under a stable canonical name that is the name of its Go
+// variable in lowercase without the "Func" suffix, such as "upper" for
+// UpperFunc and "jsonencode" for JSONEncodeFunc. The table also includes
+// the functions "tobool", "tolist", "tomap", "tonumber", "toset", and
+// "tostring" built using MakeToFunc.
+//
+// The names are not namespaced. Use the WithNamespace method of the result
+// to place them in a namespace, such as "std::upper".
+//
+// Each call returns a new table, which the caller may modify.
+func Table() *function.Table {
+	t := function.NewTable()
+	for name, f := range map[string]function.Function{
+		"absolute":               AbsoluteFunc,
+		"add":                    AddFunc,
+		"and":                    AndFunc,
+		"assertnotnull":          AssertNotNullFunc,
+		"byteslen":               BytesLenFunc,
+		"bytesslice":             BytesSliceFunc,
+		"ceil":                   CeilFunc,
+		"chomp":                  ChompFunc,
+		"chunklist":              ChunklistFunc,
+		"coalesce":               CoalesceFunc,
+		"coalescelist":           CoalesceListFunc,
+		"compact":                CompactFunc,
+		"concat":                 ConcatFunc,
+		"contains":               ContainsFunc,
+		"csvdecode":              CSVDecodeFunc,
+		"distinct":               DistinctFunc,
+		"divide":                 DivideFunc,
+		"element":                ElementFunc,
+		"equal":                  EqualFunc,
+		"flatten":                FlattenFunc,
+		"floor":                  FloorFunc,
+		"format":                 FormatFunc,
+		"formatdate":             FormatDateFunc,
+		"formatlist":             FormatListFunc,
+		"greaterthan":            GreaterThanFunc,
+		"greaterthanorequalto":   GreaterThanOrEqualToFunc,
+		"hasindex":               HasIndexFunc,
+		"indent":                 IndentFunc,
+		"index":                  IndexFunc,
+		"int":                    IntFunc,
+		"join":                   JoinFunc,
+		"jsondecode":             JSONDecodeFunc,
+		"jsonencode":             JSONEncodeFunc,
+		"keys":                   KeysFunc,
+		"length":                 LengthFunc,
+		"lessthan":               LessThanFunc,
+		"lessthanorequalto":      LessThanOrEqualToFunc,
+		"log":                    LogFunc,
+		"lookup":                 LookupFunc,
+		"lower":                  LowerFunc,
+		"max":                    MaxFunc,
+		"merge":                  MergeFunc,
+		"min":                    MinFunc,
+		"modulo":                 ModuloFunc,
+		"multiply":               MultiplyFunc,
+		"negate":                 NegateFunc,
+		"not":                    NotFunc,
+		"notequal":               NotEqualFunc,
+		"or":                     OrFunc,
+		"parseint":               ParseIntFunc,
+		"pow":                    PowFunc,
+		"range":                  RangeFunc,
+		"regex":                  RegexFunc,
+		"regexall":               RegexAllFunc,
+		"regexreplace":           RegexReplaceFunc,
+		"replace":                ReplaceFunc,
+		"reverse":                ReverseFunc,
+		"reverselist":            ReverseListFunc,
+		"sethaselement":          SetHasElementFunc,
+		"setintersection":        SetIntersectionFunc,
+		"setproduct":             SetProductFunc,
+		"setsubtract":            SetSubtractFunc,
+		"setsymmetricdifference": SetSymmetricDifferenceFunc,
+		"setunion":               SetUnionFunc,
+		"signum":                 SignumFunc,
+		"slice":                  SliceFunc,
+		"sort":                   SortFunc,
+		"split":                  SplitFunc,
+		"strlen":                 StrlenFunc,
+		"substr":                 SubstrFunc,
+		"subtract":               SubtractFunc,
+		"timeadd":                TimeAddFunc,
+		"title":                  TitleFunc,
+		"tobool":                 MakeToFunc(cty.Bool),
+		"tolist":                 MakeToFunc(cty.List(cty.DynamicPseudoType)),
+		"tomap":                  MakeToFunc(cty.Map(cty.DynamicPseudoType)),
+		"tonumber":               MakeToFunc(cty.Number),
+		"toset":                  MakeToFunc(cty.Set(cty.DynamicPseudoType)),
+		"tostring":               MakeToFunc(cty.String),
+		"trim":                   TrimFunc,
+		"trimprefix":             TrimPrefixFunc,
+		"trimspace":              TrimSpaceFunc,
+		"trimsuffix":             TrimSuffixFunc,
+		"upper":                  UpperFunc,
+		"values":                 ValuesFunc,
+		"zipmap":                 ZipmapFunc,
+	} {
+		if err := t.Add(name, f); err != nil {
+			panic(err) // all of the names above are unique
+		}
+	}
+	return t
+}
@@ -0,0 +1,38 @@
+package stdlib
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestTable(t *testing.T) {
+	tbl := Table()
+
+	f, ok := tbl.Lookup("upper")
+	if !ok {
+		t.Fatalf("no upper function")
+	}
+	if f != UpperFunc {
+		t.Errorf("upper is not UpperFunc")
+	}
+	if f, _ := tbl.Lookup("jsonencode"); f != JSONEncodeFunc {
+		t.Errorf("jsonencode is not JSONEncodeFunc")
+	}
+
+	got, err := tbl.WithNamespace("std").Map()["std::tostring"].Call([]cty.Value{cty.NumberIntVal(5)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := cty.StringVal("5"); !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	// Every function should have a description, since this table is
+	// intended to be used as a catalog.
+	for name, f := range tbl.All() {
+		if f.Description() == "" {
+			t.Errorf("function %q has no description", name)
+		}
+	}
+}
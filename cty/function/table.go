@@ -0,0 +1,259 @@
+package function
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"strings"
+)
+
+// NamespaceSeparator separates the namespace from the rest of a function
+// name in a Table, as in "std::upper".
+const NamespaceSeparator = "::"
+
+// Table is a collection of functions, each available under a unique
+// canonical name and optionally under some additional alias names.
+//
+// A name is either a simple identifier like "upper", or a namespaced name
+// made of identifiers separated by NamespaceSeparator, like "std::upper".
+// Each identifier must start with a letter or underscore, followed by any
+// number of letters, digits, underscores, or dashes.
+//
+// Create a Table using NewTable. A Table is not safe to modify concurrently
+// with any other use, but once an application has finished adding functions
+// to it a Table may be read concurrently.
+type Table struct {
+	funcs map[string]Function
+
+	// aliases maps each alias to the canonical name it refers to.
+	aliases map[string]string
+}
+
+// NewTable returns a new, empty Table.
+func NewTable() *Table {
+	return &Table{
+		funcs:   make(map[string]Function),
+		aliases: make(map[string]string),
+	}
+}
+
+// Add adds a function to the table under the given canonical name.
+//
+// Add returns a TableConflictError if the name is already used for another
+// function or alias in the table, in which case the table is unchanged. It
+// panics if the name is not a valid function name.
+func (t *Table) Add(name string, f Function) error {
+	assertValidName(name)
+	if t.defines(name) {
+		return TableConflictError{Names: []string{name}}
+	}
+	t.funcs[name] = f
+	return nil
+}
+
+// AddAlias adds an additional name for the function that is already in the
+// table under the given name, which may itself be an alias.
+//
+// AddAlias returns a TableConflictError if the alias is already used for
+// another function or alias in the table, or an error if the table has no
+// function with the given name. In either case, the table is unchanged. It
+// panics if the alias is not a valid function name.
+func (t *Table) AddAlias(alias, name string) error {
+	assertValidName(alias)
+	canonical, ok := t.CanonicalName(name)
+	if !ok {
+		return fmt.Errorf("there is no function named %q", name)
+	}
+	if t.defines(alias) {
+		return TableConflictError{Names: []string{alias}}
+	}
+	t.aliases[alias] = canonical
+	return nil
+}
+
+// Lookup returns the function with the given canonical name or alias, and
+// whether the table has such a function.
+func (t *Table) Lookup(name string) (Function, bool) {
+	canonical, ok := t.CanonicalName(name)
+	if !ok {
+		return Function{}, false
+	}
+	return t.funcs[canonical], true
+}
+
+// CanonicalName returns the canonical name of the function with the given
+// canonical name or alias, and whether the table has such a function.
+func (t *Table) CanonicalName(name string) (string, bool) {
+	if _, ok := t.funcs[name]; ok {
+		return name, true
+	}
+	canonical, ok := t.aliases[name]
+	return canonical, ok
+}
+
+// Aliases returns the aliases of the function with the given canonical name
+// or alias, in lexical order. The result does not include the canonical
+// name itself.
+func (t *Table) Aliases(name string) []string {
+	canonical, ok := t.CanonicalName(name)
+	if !ok {
+		return nil
+	}
+	var ret []string
+	for alias, target := range t.aliases {
+		if target == canonical {
+			ret = append(ret, alias)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Names returns the canonical names of all of the functions in the table, in
+// lexical order.
+func (t *Table) Names() []string {
+	ret := make([]string, 0, len(t.funcs))
+	for name := range t.funcs {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Len returns the number of functions in the table, not counting aliases.
+func (t *Table) Len() int {
+	return len(t.funcs)
+}
+
+// All returns an iterator over the functions in the table and their
+// canonical names, in lexical order of name.
+func (t *Table) All() iter.Seq2[string, Function] {
+	return func(yield func(string, Function) bool) {
+		for _, name := range t.Names() {
+			if !yield(name, t.funcs[name]) {
+				return
+			}
+		}
+	}
+}
+
+// Map returns a map from every canonical name and alias in the table to the
+// corresponding function, for use with APIs that expect functions in a map.
+//
+// The result is a new map that the caller may modify.
+func (t *Table) Map() map[string]Function {
+	ret := make(map[string]Function, len(t.funcs)+len(t.aliases))
+	for name, f := range t.funcs {
+		ret[name] = f
+	}
+	for alias, name := range t.aliases {
+		ret[alias] = t.funcs[name]
+	}
+	return ret
+}
+
+// Merge adds all of the functions and aliases from the other table to the
+// receiver.
+//
+// Merge returns a TableConflictError listing every name that the two tables
+// both use, in which case the receiver is unchanged.
+func (t *Table) Merge(other *Table) error {
+	var conflicts []string
+	for name := range other.funcs {
+		if t.defines(name) {
+			conflicts = append(conflicts, name)
+		}
+	}
+	for alias := range other.aliases {
+		if t.defines(alias) {
+			conflicts = append(conflicts, alias)
+		}
+	}
+	if len(conflicts) != 0 {
+		sort.Strings(conflicts)
+		return TableConflictError{Names: conflicts}
+	}
+
+	for name, f := range other.funcs {
+		t.funcs[name] = f
+	}
+	for alias, name := range other.aliases {
+		t.aliases[alias] = name
+	}
+	return nil
+}
+
+// WithNamespace returns a new table with the same functions and aliases as
+// the receiver, but with each name prefixed by the given namespace and
+// NamespaceSeparator. For example, a function named "upper" becomes
+// "std::upper" in the namespace "std".
+//
+// WithNamespace panics if the namespace is not a valid function name, which
+// may itself be namespaced.
+func (t *Table) WithNamespace(namespace string) *Table {
+	assertValidName(namespace)
+	prefix := namespace + NamespaceSeparator
+	ret := NewTable()
+	for name, f := range t.funcs {
+		ret.funcs[prefix+name] = f
+	}
+	for alias, name := range t.aliases {
+		ret.aliases[prefix+alias] = prefix + name
+	}
+	return ret
+}
+
+func (t *Table) defines(name string) bool {
+	_, ok := t.CanonicalName(name)
+	return ok
+}
+
+// TableConflictError is returned when adding a function or alias to a Table,
+// or merging two Tables, would give a name more than one meaning.
+type TableConflictError struct {
+	// Names are the conflicting names, in lexical order.
+	Names []string
+}
+
+func (e TableConflictError) Error() string {
+	if len(e.Names) == 1 {
+		return fmt.Sprintf("a function named %q is already defined", e.Names[0])
+	}
+	quoted := make([]string, len(e.Names))
+	for i, name := range e.Names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	return fmt.Sprintf("functions named %s are already defined", strings.Join(quoted, ", "))
+}
+
+// ValidName returns true if the given string is a valid name for a function
+// in a Table, which may be namespaced.
+func ValidName(name string) bool {
+	for _, ident := range strings.Split(name, NamespaceSeparator) {
+		if !validIdentifier(ident) {
+			return false
+		}
+	}
+	return true
+}
+
+func validIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		case i > 0 && (r == '-' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func assertValidName(name string) {
+	if !ValidName(name) {
+		panic(fmt.Sprintf("invalid function name %q", name))
+	}
+}
@@ -0,0 +1,178 @@
+package function
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestTable(t *testing.T) {
+	upper := New(&Spec{
+		Params: []Parameter{{Name: "str", Type: cty.String}},
+		Type:   StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return args[0], nil
+		},
+	})
+	lower := New(&Spec{
+		Params: []Parameter{{Name: "str", Type: cty.String}},
+		Type:   StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return args[0], nil
+		},
+	})
+
+	tbl := NewTable()
+	if err := tbl.Add("upper", upper); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := tbl.Add("lower", lower); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := tbl.AddAlias("toupper", "upper"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := tbl.AddAlias("caps", "toupper"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if f, ok := tbl.Lookup("caps"); !ok || f != upper {
+		t.Errorf("wrong function for alias of alias")
+	}
+	if name, ok := tbl.CanonicalName("caps"); !ok || name != "upper" {
+		t.Errorf("wrong canonical name %q for alias of alias", name)
+	}
+	if _, ok := tbl.Lookup("nonexist"); ok {
+		t.Errorf("found nonexistent function")
+	}
+	if got, want := tbl.Aliases("toupper"), []string{"caps", "toupper"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong aliases\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := tbl.Names(), []string{"lower", "upper"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong names\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := tbl.Len(), 2; got != want {
+		t.Errorf("wrong length %d; want %d", got, want)
+	}
+
+	var gotNames []string
+	for name, f := range tbl.All() {
+		gotNames = append(gotNames, name)
+		if want, _ := tbl.Lookup(name); f != want {
+			t.Errorf("wrong function for %q during iteration", name)
+		}
+	}
+	if want := []string{"lower", "upper"}; !reflect.DeepEqual(gotNames, want) {
+		t.Errorf("wrong names from iteration\ngot:  %q\nwant: %q", gotNames, want)
+	}
+
+	m := tbl.Map()
+	if got, want := len(m), 4; got != want {
+		t.Errorf("wrong map length %d; want %d", got, want)
+	}
+	if m["caps"] != upper || m["lower"] != lower {
+		t.Errorf("wrong functions in map")
+	}
+
+	var conflict TableConflictError
+	err := tbl.Add("toupper", lower)
+	if !errors.As(err, &conflict) || !reflect.DeepEqual(conflict.Names, []string{"toupper"}) {
+		t.Errorf("wrong error for conflicting name: %v", err)
+	}
+	if got, want := err.Error(), `a function named "toupper" is already defined`; got != want {
+		t.Errorf("wrong error message\ngot:  %s\nwant: %s", got, want)
+	}
+	if err := tbl.AddAlias("lower", "upper"); !errors.As(err, &conflict) {
+		t.Errorf("wrong error for conflicting alias: %v", err)
+	}
+	if err := tbl.AddAlias("small", "nonexist"); err == nil {
+		t.Errorf("no error for alias of nonexistent function")
+	}
+}
+
+func TestTableMerge(t *testing.T) {
+	f := New(&Spec{
+		Type: StaticReturnType(cty.Bool),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return cty.True, nil
+		},
+	})
+
+	a := NewTable()
+	a.Add("a", f)
+	a.Add("shared", f)
+	a.AddAlias("alias", "a")
+
+	b := NewTable()
+	b.Add("b", f)
+	b.Add("alias", f)
+	b.AddAlias("shared", "b")
+
+	err := a.Merge(b)
+	var conflict TableConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("wrong error for conflicting tables: %v", err)
+	}
+	if got, want := conflict.Names, []string{"alias", "shared"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong conflicting names\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := err.Error(), `functions named "alias", "shared" are already defined`; got != want {
+		t.Errorf("wrong error message\ngot:  %s\nwant: %s", got, want)
+	}
+	if _, ok := a.Lookup("b"); ok {
+		t.Errorf("table was modified by a failed merge")
+	}
+
+	std := b.WithNamespace("std")
+	if got, want := std.Names(), []string{"std::alias", "std::b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong names in namespace\ngot:  %q\nwant: %q", got, want)
+	}
+	if name, _ := std.CanonicalName("std::shared"); name != "std::b" {
+		t.Errorf("wrong canonical name %q for namespaced alias", name)
+	}
+
+	if err := a.Merge(std); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got, want := a.Names(), []string{"a", "shared", "std::alias", "std::b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong names after merge\ngot:  %q\nwant: %q", got, want)
+	}
+	if got, want := a.Aliases("std::b"), []string{"std::shared"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong aliases after merge\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestValidName(t *testing.T) {
+	tests := map[string]bool{
+		"upper":        true,
+		"_private":     true,
+		"to-string":    true,
+		"base64encode": true,
+		"std::upper":   true,
+		"a::b::c":      true,
+		"":             false,
+		"1st":          false,
+		"-x":           false,
+		"std::":        false,
+		"::upper":      false,
+		"std:upper":    false,
+		"std::::upper": false,
+		"has space":    false,
+	}
+	for name, want := range tests {
+		if got := ValidName(name); got != want {
+			t.Errorf("wrong result for %q: %t; want %t", name, got, want)
+		}
+	}
+}
+
+func TestTableInvalidName(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("no panic for invalid name")
+		}
+	}()
+	NewTable().Add("std:upper", Function{})
+}
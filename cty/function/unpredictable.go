@@ -23,6 +23,7 @@ import (
 func Unpredictable(f Function) Function {
 	newSpec := *f.spec // shallow copy
 	newSpec.Impl = unpredictableImpl
+	newSpec.ImplWithWarnings = nil
 	return New(&newSpec)
 }
 
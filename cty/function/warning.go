@@ -0,0 +1,120 @@
+package function
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Deprecation describes why a function or parameter is deprecated, for use
+// in Spec.Deprecated and Parameter.Deprecated.
+type Deprecation struct {
+	// Message is an optional explanation of why the function or parameter
+	// is deprecated, or of what callers should do instead.
+	Message string
+
+	// Replacement is the optional name of a function or parameter that
+	// callers should use instead.
+	Replacement string
+
+	// Since is the optional version in which the function or parameter was
+	// deprecated. The format of the version is up to the application.
+	Since string
+}
+
+// describe returns a sentence describing the deprecation of the given
+// subject, such as "this function".
+func (d *Deprecation) describe(subject string) string {
+	var buf strings.Builder
+	buf.WriteString(subject)
+	buf.WriteString(" is deprecated")
+	if d.Since != "" {
+		fmt.Fprintf(&buf, " since %s", d.Since)
+	}
+	if d.Replacement != "" {
+		fmt.Fprintf(&buf, "; use %s instead", d.Replacement)
+	}
+	if d.Message != "" {
+		buf.WriteString(": ")
+		buf.WriteString(d.Message)
+	}
+	return buf.String()
+}
+
+// copy returns a copy of the receiver, so that callers can't modify the
+// function's specification through a returned pointer.
+func (d *Deprecation) copy() *Deprecation {
+	ret := *d
+	return &ret
+}
+
+// Warning describes a problem with a function call that does not prevent it
+// from succeeding, as returned by Function.CallWithWarnings.
+type Warning struct {
+	// Message describes the problem in English.
+	Message string
+
+	// Index is the zero-based index of the argument that the warning relates
+	// to, or -1 if the warning relates to the call as a whole.
+	Index int
+
+	// Deprecation is set if the warning is about the use of a deprecated
+	// function or parameter, in which case it describes the deprecation.
+	Deprecation *Deprecation
+}
+
+// NewWarningf returns a Warning about the call as a whole, with a message
+// produced by passing the given format and arguments to fmt.Sprintf.
+func NewWarningf(f string, args ...any) Warning {
+	return Warning{
+		Message: fmt.Sprintf(f, args...),
+		Index:   -1,
+	}
+}
+
+// NewArgWarningf returns a Warning about the argument with the given index,
+// with a message produced by passing the given format and arguments to
+// fmt.Sprintf.
+func NewArgWarningf(i int, f string, args ...any) Warning {
+	return Warning{
+		Message: fmt.Sprintf(f, args...),
+		Index:   i,
+	}
+}
+
+// deprecationWarnings returns the warnings about the function and its
+// parameters being deprecated for a call with the given arguments. Arguments
+// that the caller omitted may be given as cty.NilVal, and are ignored along
+// with null arguments.
+func (f Function) deprecationWarnings(args []cty.Value) []Warning {
+	var ret []Warning
+	if d := f.spec.Deprecated; d != nil {
+		ret = append(ret, Warning{
+			Message:     d.describe("this function"),
+			Index:       -1,
+			Deprecation: d.copy(),
+		})
+	}
+	for i, arg := range args {
+		var param *Parameter
+		if i < len(f.spec.Params) {
+			param = &f.spec.Params[i]
+		} else {
+			param = f.spec.VarParam
+		}
+		if param == nil || param.Deprecated == nil || arg == cty.NilVal || arg.IsNull() {
+			continue
+		}
+		subject := fmt.Sprintf("argument %d", i+1)
+		if param.Name != "" {
+			subject = fmt.Sprintf("the %q argument", param.Name)
+		}
+		ret = append(ret, Warning{
+			Message:     param.Deprecated.describe(subject),
+			Index:       i,
+			Deprecation: param.Deprecated.copy(),
+		})
+	}
+	return ret
+}
@@ -0,0 +1,226 @@
+package function
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestCallWithWarnings(t *testing.T) {
+	oldDeprecation := &Deprecation{
+		Message:     "it is too slow",
+		Replacement: "new",
+		Since:       "v1.2.0",
+	}
+	f := New(&Spec{
+		Deprecated: oldDeprecation,
+		Params: []Parameter{
+			{
+				Name: "str",
+				Type: cty.String,
+			},
+			{
+				Name:       "legacy",
+				Type:       cty.Bool,
+				AllowNull:  true,
+				Deprecated: &Deprecation{},
+			},
+		},
+		VarParam: &Parameter{
+			Type:         cty.String,
+			AllowUnknown: true,
+			Deprecated:   &Deprecation{Message: "pass a list instead"},
+		},
+		Type: StaticReturnType(cty.String),
+		ImplWithWarnings: func(args []cty.Value, retType cty.Type) (cty.Value, []Warning, error) {
+			if args[0].AsString() == "" {
+				return cty.NilVal, []Warning{NewWarningf("ignored")}, errors.New("empty string")
+			}
+			return args[0], []Warning{NewArgWarningf(0, "string is %q", args[0].AsString())}, nil
+		},
+	})
+
+	type warning struct {
+		Message    string
+		Index      int
+		Deprecated bool
+	}
+	tests := map[string]struct {
+		Args         []cty.Value
+		WantVal      cty.Value
+		WantWarnings []warning
+		WantErr      bool
+	}{
+		"no deprecated arguments": {
+			Args:    []cty.Value{cty.StringVal("a"), cty.NullVal(cty.Bool)},
+			WantVal: cty.StringVal("a"),
+			WantWarnings: []warning{
+				{`this function is deprecated since v1.2.0; use new instead: it is too slow`, -1, true},
+				{`string is "a"`, 0, false},
+			},
+		},
+		"deprecated arguments": {
+			Args:    []cty.Value{cty.StringVal("a"), cty.True, cty.StringVal("b"), cty.StringVal("c")},
+			WantVal: cty.StringVal("a"),
+			WantWarnings: []warning{
+				{`this function is deprecated since v1.2.0; use new instead: it is too slow`, -1, true},
+				{`the "legacy" argument is deprecated`, 1, true},
+				{`argument 3 is deprecated: pass a list instead`, 2, true},
+				{`argument 4 is deprecated: pass a list instead`, 3, true},
+				{`string is "a"`, 0, false},
+			},
+		},
+		"unknown result": {
+			Args:    []cty.Value{cty.UnknownVal(cty.String), cty.NullVal(cty.Bool)},
+			WantVal: cty.UnknownVal(cty.String),
+			WantWarnings: []warning{
+				{`this function is deprecated since v1.2.0; use new instead: it is too slow`, -1, true},
+			},
+		},
+		"error": {
+			Args:    []cty.Value{cty.StringVal(""), cty.NullVal(cty.Bool)},
+			WantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, gotWarnings, err := f.CallWithWarnings(test.Args)
+			if test.WantErr {
+				if err == nil {
+					t.Fatalf("unexpected success")
+				}
+				if gotWarnings != nil {
+					t.Fatalf("unexpected warnings with error: %#v", gotWarnings)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.WantVal) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.WantVal)
+			}
+
+			var simplified []warning
+			for _, w := range gotWarnings {
+				simplified = append(simplified, warning{w.Message, w.Index, w.Deprecation != nil})
+			}
+			if !reflect.DeepEqual(simplified, test.WantWarnings) {
+				t.Errorf("wrong warnings\ngot:  %#v\nwant: %#v", simplified, test.WantWarnings)
+			}
+
+			// Call returns the same result, without the warnings.
+			got, err = f.Call(test.Args)
+			if err != nil {
+				t.Fatalf("unexpected error from Call: %s", err)
+			}
+			if !got.RawEquals(test.WantVal) {
+				t.Errorf("wrong result from Call\ngot:  %#v\nwant: %#v", got, test.WantVal)
+			}
+		})
+	}
+
+	if got := f.Deprecated(); !reflect.DeepEqual(got, oldDeprecation) {
+		t.Errorf("wrong deprecation\ngot:  %#v\nwant: %#v", got, oldDeprecation)
+	}
+	if got := Unpredictable(f).Deprecated(); !reflect.DeepEqual(got, oldDeprecation) {
+		t.Errorf("wrong deprecation for unpredictable function\ngot:  %#v\nwant: %#v", got, oldDeprecation)
+	}
+	got, err := Unpredictable(f).Call([]cty.Value{cty.StringVal("a"), cty.NullVal(cty.Bool)})
+	if err != nil {
+		t.Fatalf("unexpected error from unpredictable function: %s", err)
+	}
+	if !got.RawEquals(cty.UnknownVal(cty.String)) {
+		t.Errorf("wrong result from unpredictable function: %#v", got)
+	}
+}
+
+func TestCallWithWarningsNotDeprecated(t *testing.T) {
+	f := New(&Spec{
+		Params: []Parameter{{Type: cty.String}},
+		Type:   StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return args[0], nil
+		},
+	})
+	_, warnings, err := f.CallWithWarnings([]cty.Value{cty.StringVal("a")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if warnings != nil {
+		t.Errorf("unexpected warnings: %#v", warnings)
+	}
+	if f.Deprecated() != nil {
+		t.Errorf("unexpected deprecation")
+	}
+}
+
+func TestCallWithWarningsCopiesDeprecation(t *testing.T) {
+	f := New(&Spec{
+		Deprecated: &Deprecation{Replacement: "new"},
+		Params: []Parameter{
+			{
+				Name:       "a",
+				Type:       cty.String,
+				Deprecated: &Deprecation{Replacement: "b"},
+			},
+		},
+		Type: StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return args[0], nil
+		},
+	})
+
+	_, warnings, err := f.CallWithWarnings([]cty.Value{cty.StringVal("a")})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("wrong number of warnings %d; want 2", len(warnings))
+	}
+
+	// Modifying the returned warnings must not change the function.
+	for _, w := range warnings {
+		w.Deprecation.Replacement = "modified"
+	}
+	if got, want := f.Deprecated().Replacement, "new"; got != want {
+		t.Errorf("function deprecation was modified\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := f.Params()[0].Deprecated.Replacement, "b"; got != want {
+		t.Errorf("parameter deprecation was modified\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestParamsCopyDeprecation(t *testing.T) {
+	f := New(&Spec{
+		Params: []Parameter{
+			{
+				Name:       "a",
+				Type:       cty.String,
+				Deprecated: &Deprecation{Replacement: "b"},
+			},
+		},
+		VarParam: &Parameter{
+			Name:       "rest",
+			Type:       cty.String,
+			Deprecated: &Deprecation{Replacement: "c"},
+		},
+		Type: StaticReturnType(cty.String),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return args[0], nil
+		},
+	})
+
+	// Modifying the returned parameters must not change the function.
+	f.Params()[0].Deprecated.Replacement = "modified"
+	f.VarParam().Deprecated.Replacement = "modified"
+	if got, want := f.Params()[0].Deprecated.Replacement, "b"; got != want {
+		t.Errorf("parameter deprecation was modified\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := f.VarParam().Deprecated.Replacement, "c"; got != want {
+		t.Errorf("variadic parameter deprecation was modified\ngot:  %s\nwant: %s", got, want)
+	}
+}
@@ -41,3 +41,14 @@ func structTagIndices(st reflect.Type) map[string]int {
 
 	return ret
 }
+
+// targetType returns the cty type implied by the given Go type, for use in
+// errors that describe why a value cannot be assigned to a target of that
+// type, or cty.NilType if there is no such cty type.
+func targetType(rt reflect.Type) cty.Type {
+	ty, err := impliedType(rt, nil)
+	if err != nil {
+		return cty.NilType
+	}
+	return ty
+}
@@ -1,11 +1,14 @@
 package gocty
 
 import (
+	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"reflect"
 
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 // FromCtyValue assigns a cty.Value to a reflect.Value, which must be a pointer,
@@ -65,7 +68,7 @@ func fromCtyValue(val cty.Value, target reflect.Value, path cty.Path) error {
 	if val.IsNull() && !val.Type().IsListType() && !val.Type().IsMapType() && !val.Type().IsCapsuleType() {
 		target = fromCtyPopulatePtr(target, true)
 		if target.Kind() != reflect.Ptr {
-			return path.NewErrorf("null value is not allowed")
+			return nullNotAllowedError(path, val, target)
 		}
 
 		target.Set(reflect.Zero(target.Type()))
@@ -75,7 +78,11 @@ func fromCtyValue(val cty.Value, target reflect.Value, path cty.Path) error {
 	target = deepTarget
 
 	if !val.IsKnown() {
-		return path.NewErrorf("value must be known")
+		return path.NewError(convert.UnknownNotAllowedError{
+			Got:  ty,
+			Want: targetType(target.Type()),
+			Err:  errors.New("value must be known"),
+		})
 	}
 
 	switch ty {
@@ -167,7 +174,7 @@ func fromCtyNumberInt(bf *big.Float, target reflect.Value, path cty.Path) error
 
 	iv, accuracy := bf.Int64()
 	if accuracy != big.Exact || iv < min || iv > max {
-		return path.NewErrorf("value must be a whole number, between %d and %d", min, max)
+		return lossyNumberError(path, bf, target, "value must be a whole number, between %d and %d", min, max)
 	}
 
 	target.SetInt(iv)
@@ -193,7 +200,7 @@ func fromCtyNumberUInt(bf *big.Float, target reflect.Value, path cty.Path) error
 
 	iv, accuracy := bf.Uint64()
 	if accuracy != big.Exact || iv > max {
-		return path.NewErrorf("value must be a whole number, between 0 and %d inclusive", max)
+		return lossyNumberError(path, bf, target, "value must be a whole number, between 0 and %d inclusive", max)
 	}
 
 	target.SetUint(iv)
@@ -208,7 +215,7 @@ func fromCtyNumberFloat(bf *big.Float, target reflect.Value, path cty.Path) erro
 			// We allow the precision to be truncated as part of our conversion,
 			// but we don't want to silently introduce infinities.
 			if math.IsInf(fv, 0) {
-				return path.NewErrorf("value must be between %f and %f inclusive", -math.MaxFloat64, math.MaxFloat64)
+				return lossyNumberError(path, bf, target, "value must be between %f and %f inclusive", -math.MaxFloat64, math.MaxFloat64)
 			}
 		}
 		target.SetFloat(fv)
@@ -229,7 +236,7 @@ func fromCtyNumberBig(bf *big.Float, target reflect.Value, path cty.Path) error
 	case bigIntType.ConvertibleTo(target.Type()):
 		bi, accuracy := bf.Int(nil)
 		if accuracy != big.Exact {
-			return path.NewErrorf("value must be a whole number")
+			return lossyNumberError(path, bf, target, "value must be a whole number")
 		}
 		target.Set(reflect.ValueOf(bi).Elem().Convert(target.Type()))
 		return nil
@@ -292,7 +299,7 @@ func fromCtyList(val cty.Value, target reflect.Value, path cty.Path) error {
 
 	case reflect.Array:
 		if val.IsNull() {
-			return path.NewErrorf("null value is not allowed")
+			return nullNotAllowedError(path, val, target)
 		}
 
 		length := val.LengthInt()
@@ -410,7 +417,7 @@ func fromCtySet(val cty.Value, target reflect.Value, path cty.Path) error {
 
 	case reflect.Array:
 		if val.IsNull() {
-			return path.NewErrorf("null value is not allowed")
+			return nullNotAllowedError(path, val, target)
 		}
 
 		length := val.LengthInt()
@@ -464,7 +471,12 @@ func fromCtyObject(val cty.Value, target reflect.Value, path cty.Path) error {
 				case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
 					// okay
 				default:
-					return path.NewErrorf("missing required attribute %q", k)
+					return path.NewError(convert.MissingAttributeError{
+						Names: []string{k},
+						Got:   val.Type(),
+						Want:  targetType(target.Type()),
+						Err:   fmt.Errorf("missing required attribute %q", k),
+					})
 				}
 			}
 		}
@@ -476,7 +488,12 @@ func fromCtyObject(val cty.Value, target reflect.Value, path cty.Path) error {
 
 			fieldIdx, exists := targetFields[k]
 			if !exists {
-				return path.NewErrorf("unsupported attribute %q", k)
+				return path.NewError(convert.UnsupportedAttributeError{
+					Name: k,
+					Got:  val.Type(),
+					Want: targetType(target.Type()),
+					Err:  fmt.Errorf("unsupported attribute %q", k),
+				})
 			}
 
 			ev := val.GetAttr(k)
@@ -568,7 +585,7 @@ func fromCtyCapsule(val cty.Value, target reflect.Value, path cty.Path) error {
 		return nil
 	} else {
 		if val.IsNull() {
-			return path.NewErrorf("null value is not allowed")
+			return nullNotAllowedError(path, val, target)
 		}
 
 		// If our target isn't a pointer then we will attempt to copy
@@ -633,6 +650,27 @@ func fromCtyPopulatePtr(target reflect.Value, decodingNull bool) reflect.Value {
 	return target
 }
 
+// nullNotAllowedError returns an error reporting that the given null value
+// cannot be assigned to the given target.
+func nullNotAllowedError(path cty.Path, val cty.Value, target reflect.Value) error {
+	return path.NewError(convert.NullNotAllowedError{
+		Got:  val.Type(),
+		Want: targetType(target.Type()),
+		Err:  errors.New("null value is not allowed"),
+	})
+}
+
+// lossyNumberError returns an error reporting that the given number cannot
+// be represented exactly in the given target, using the given message.
+func lossyNumberError(path cty.Path, bf *big.Float, target reflect.Value, f string, args ...any) error {
+	return path.NewError(convert.LossyNumberError{
+		Value: cty.NumberVal(bf),
+		Got:   cty.Number,
+		Want:  targetType(target.Type()),
+		Err:   fmt.Errorf(f, args...),
+	})
+}
+
 // likelyRequiredTypesError returns an error that states which types are
 // acceptable by making some assumptions about what types we support for
 // each target Go kind. It's not a precise science but it allows us to return
@@ -1,12 +1,14 @@
 package gocty
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
 )
 
 func TestOut(t *testing.T) {
@@ -374,6 +376,96 @@ func TestOut(t *testing.T) {
 	}
 }
 
+func TestOutErrors(t *testing.T) {
+	tests := []struct {
+		CtyValue   cty.Value
+		TargetType reflect.Type
+		WantErr    any // pointer to the error type that errors.As should find
+		WantMsg    string
+	}{
+		{
+			CtyValue:   cty.NullVal(cty.Number),
+			TargetType: reflect.TypeOf(0),
+			WantErr:    &convert.NullNotAllowedError{},
+			WantMsg:    "null value is not allowed",
+		},
+		{
+			CtyValue:   cty.UnknownVal(cty.String),
+			TargetType: reflect.TypeOf(""),
+			WantErr:    &convert.UnknownNotAllowedError{},
+			WantMsg:    "value must be known",
+		},
+		{
+			CtyValue:   cty.NumberFloatVal(1.5),
+			TargetType: reflect.TypeOf(0),
+			WantErr:    &convert.LossyNumberError{},
+			WantMsg:    "value must be a whole number, between -9223372036854775808 and 9223372036854775807",
+		},
+		{
+			CtyValue:   cty.NumberIntVal(256),
+			TargetType: reflect.TypeOf(uint8(0)),
+			WantErr:    &convert.LossyNumberError{},
+			WantMsg:    "value must be a whole number, between 0 and 255 inclusive",
+		},
+		{
+			CtyValue: cty.ObjectVal(map[string]cty.Value{
+				"number": cty.NumberIntVal(1),
+			}),
+			TargetType: reflect.TypeOf(testStruct{}),
+			WantErr:    &convert.MissingAttributeError{},
+			WantMsg:    `missing required attribute "name"`,
+		},
+		{
+			CtyValue: cty.ObjectVal(map[string]cty.Value{
+				"name":  cty.StringVal("a"),
+				"extra": cty.True,
+			}),
+			TargetType: reflect.TypeOf(testStruct{}),
+			WantErr:    &convert.UnsupportedAttributeError{},
+			WantMsg:    `unsupported attribute "extra"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v into %s", test.CtyValue, test.TargetType), func(t *testing.T) {
+			target := reflect.New(test.TargetType)
+			err := FromCtyValue(test.CtyValue, target.Interface())
+			if err == nil {
+				t.Fatalf("unexpected success")
+			}
+			if got, want := err.Error(), test.WantMsg; got != want {
+				t.Errorf("wrong message\ngot:  %s\nwant: %s", got, want)
+			}
+			if !errors.As(err, test.WantErr) {
+				t.Errorf("wrong error type %#v; want %T", err, test.WantErr)
+			}
+		})
+	}
+
+	t.Run("types", func(t *testing.T) {
+		val := cty.ObjectVal(map[string]cty.Value{
+			"number": cty.NumberIntVal(1),
+		})
+		var target testStruct
+		err := FromCtyValue(val, &target)
+
+		var got convert.MissingAttributeError
+		if !errors.As(err, &got) {
+			t.Fatalf("error is not a MissingAttributeError: %#v", err)
+		}
+		wantTy := cty.Object(map[string]cty.Type{
+			"name":   cty.String,
+			"number": cty.Number,
+		})
+		if !got.Got.Equals(val.Type()) || !got.Want.Equals(wantTy) {
+			t.Errorf("wrong types\ngot:  %#v\nwant: %#v", got.Got, got.Want)
+		}
+		if len(got.Names) != 1 || got.Names[0] != "name" {
+			t.Errorf("wrong names %#v", got.Names)
+		}
+	})
+}
+
 type testOutAssertFunc func(cty.Value, reflect.Type, any, *testing.T)
 
 func testOutAssertPtrVal(want any) testOutAssertFunc {
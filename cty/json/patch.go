@@ -0,0 +1,393 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// ApplyPatch applies the given JSON Patch document, as defined in RFC 6902,
+// to the given value and returns the result.
+//
+// The given value is first converted to the given type, and the result is
+// always of that same type. Any values inserted by the patch are decoded
+// from JSON with their implied types and then converted to the type at the
+// location where they are being inserted, and so the patch may fail if an
+// inserted value is not compatible with the type at that location.
+//
+// JSON Pointers in the patch are resolved using the type of the value being
+// patched, as described for cty.ParseJSONPointer.
+//
+// Because the result must conform to the given type, some operations have
+// slightly different meaning than in RFC 6902:
+//
+//   - "add" with the path of an existing object attribute replaces its value,
+//     because all object attributes are always present.
+//   - "remove" with the path of an object attribute sets it to null, for the
+//     same reason.
+//   - It is not possible to add or remove elements of a tuple, because that
+//     would change the tuple's type.
+//   - Sets cannot be patched, because their elements cannot be addressed by a
+//     JSON Pointer.
+//
+// If an operation fails then the result is a cty.PathError whose path is the
+// location of the problem, and whose message includes the index of the
+// failing operation within the patch.
+func ApplyPatch(patch []byte, val cty.Value, ty cty.Type) (cty.Value, error) {
+	var ops []struct {
+		Op    string          `json:"op"`
+		Path  *string         `json:"path"`
+		From  *string         `json:"from"`
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return cty.NilVal, fmt.Errorf("invalid JSON Patch document: %w", err)
+	}
+
+	val, err := convert.Convert(val, ty)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	for i, op := range ops {
+		if op.Path == nil {
+			return cty.NilVal, fmt.Errorf("invalid JSON Patch document: operation %d (%s) has no \"path\"", i, op.Op)
+		}
+		var err error
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return cty.NilVal, fmt.Errorf("invalid JSON Patch document: operation %d (%s) has no \"value\"", i, op.Op)
+			}
+		case "move", "copy":
+			if op.From == nil {
+				return cty.NilVal, fmt.Errorf("invalid JSON Patch document: operation %d (%s) has no \"from\"", i, op.Op)
+			}
+		case "remove":
+		default:
+			return cty.NilVal, fmt.Errorf("invalid JSON Patch document: operation %d has unsupported op %q", i, op.Op)
+		}
+
+		switch op.Op {
+		case "add":
+			val, err = patchAdd(val, *op.Path, func(ty cty.Type) (cty.Value, error) {
+				return decodeAndConvert(op.Value, ty)
+			})
+		case "remove":
+			val, err = patchRemove(val, *op.Path)
+		case "replace":
+			val, err = patchReplace(val, *op.Path, op.Value)
+		case "move":
+			var moved cty.Value
+			moved, err = patchGet(val, *op.From)
+			if err == nil {
+				val, err = patchMove(val, *op.From, *op.Path, moved)
+			}
+		case "copy":
+			var copied cty.Value
+			copied, err = patchGet(val, *op.From)
+			if err == nil {
+				val, err = patchAdd(val, *op.Path, func(ty cty.Type) (cty.Value, error) {
+					return convert.Convert(copied, ty)
+				})
+			}
+		case "test":
+			err = patchTest(val, *op.Path, op.Value)
+		}
+		if err != nil {
+			if pathErr, ok := err.(cty.PathError); ok {
+				return cty.NilVal, pathErr.Path.NewErrorf("operation %d (%s) failed: %w", i, op.Op, pathErr.Unwrap())
+			}
+			return cty.NilVal, fmt.Errorf("operation %d (%s) failed: %w", i, op.Op, err)
+		}
+	}
+
+	return val, nil
+}
+
+// ApplyMergePatch applies the given JSON Merge Patch document, as defined in
+// RFC 7386, to the given value and returns the result.
+//
+// The given value is first converted to the given type, and the result is
+// always of that same type. Any values inserted by the patch are decoded
+// from JSON with their implied types and then converted to the type at the
+// location where they are being inserted.
+//
+// A merge patch describes changes to JSON objects, which can correspond to
+// either object or map values. For a map, a null value in the patch removes
+// the corresponding element. For an object, a null value in the patch
+// sets the corresponding attribute to null, and patching an attribute that
+// the object type does not declare is an error. Any other value in the patch
+// replaces the corresponding value entirely.
+//
+// If the patch cannot be applied then the result is a cty.PathError whose
+// path is the location of the problem.
+func ApplyMergePatch(patch []byte, val cty.Value, ty cty.Type) (cty.Value, error) {
+	val, err := convert.Convert(val, ty)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return mergePatch(val, patch, nil)
+}
+
+func mergePatch(target cty.Value, patch []byte, path cty.Path) (cty.Value, error) {
+	ty := target.Type()
+	patch = bytes.TrimSpace(patch)
+	if len(patch) == 0 || patch[0] != '{' || !(ty.IsObjectType() || ty.IsMapType()) {
+		v, err := decodeAndConvert(patch, ty)
+		if err != nil {
+			return cty.NilVal, path.NewError(err)
+		}
+		return v, nil
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &members); err != nil {
+		return cty.NilVal, path.NewErrorf("invalid JSON Merge Patch document: %s", err)
+	}
+
+	target, marks := target.Unmark()
+	if !target.IsKnown() {
+		return cty.NilVal, path.NewErrorf("cannot apply a merge patch to an unknown value")
+	}
+
+	switch {
+	case ty.IsObjectType():
+		var attrs map[string]cty.Value
+		if target.IsNull() {
+			attrs = make(map[string]cty.Value)
+			for name, aty := range ty.AttributeTypes() {
+				attrs[name] = cty.NullVal(aty)
+			}
+		} else {
+			attrs = target.AsValueMap()
+		}
+		for name, raw := range members {
+			if !ty.HasAttribute(name) {
+				return cty.NilVal, path.NewErrorf("unsupported attribute %q", name)
+			}
+			aty := ty.AttributeType(name)
+			if isJSONNull(raw) {
+				attrs[name] = cty.NullVal(aty)
+				continue
+			}
+			v, err := mergePatch(attrs[name], raw, path.GetAttr(name))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			attrs[name] = v
+		}
+		if len(attrs) == 0 {
+			return cty.EmptyObjectVal.WithMarks(marks), nil
+		}
+		return cty.ObjectVal(attrs).WithMarks(marks), nil
+	default: // map type
+		ety := ty.ElementType()
+		elems := make(map[string]cty.Value)
+		if !target.IsNull() {
+			for k, v := range target.AsValueMap() {
+				elems[k] = v
+			}
+		}
+		for k, raw := range members {
+			if isJSONNull(raw) {
+				delete(elems, k)
+				continue
+			}
+			existing, exists := elems[k]
+			if !exists {
+				existing = cty.NullVal(ety)
+			}
+			v, err := mergePatch(existing, raw, path.IndexString(k))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			elems[k] = v
+		}
+		if len(elems) == 0 {
+			return cty.MapValEmpty(ety).WithMarks(marks), nil
+		}
+		return cty.MapVal(elems).WithMarks(marks), nil
+	}
+}
+
+func patchGet(val cty.Value, ptr string) (cty.Value, error) {
+	path, err := cty.ParseJSONPointer(ptr, val.Type())
+	if err != nil {
+		return cty.NilVal, err
+	}
+	ret, err := path.Apply(val)
+	if err != nil {
+		return cty.NilVal, path.NewErrorf("no value exists at this location")
+	}
+	return ret, nil
+}
+
+func patchAdd(val cty.Value, ptr string, newVal func(cty.Type) (cty.Value, error)) (cty.Value, error) {
+	if ptr == "" {
+		return newVal(val.Type())
+	}
+
+	// Adding to a list has special treatment because it can refer to an index
+	// that doesn't exist yet, which cty.ParseJSONPointer doesn't allow.
+	slash := strings.LastIndexByte(ptr, '/')
+	if slash < 0 {
+		// This is not a valid pointer, so ParseJSONPointer will return a
+		// suitable error for it.
+		_, err := cty.ParseJSONPointer(ptr, val.Type())
+		return cty.NilVal, err
+	}
+	parentPath, err := cty.ParseJSONPointer(ptr[:slash], val.Type())
+	if err != nil {
+		return cty.NilVal, err
+	}
+	parent, err := patchGet(val, ptr[:slash])
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if parent.Type().IsListType() {
+		return patchInsertListElem(val, parentPath, parent, ptr[slash+1:], newVal)
+	}
+	if parent.Type().IsTupleType() {
+		return cty.NilVal, parentPath.NewErrorf("cannot add an element to a tuple, because that would change its type")
+	}
+
+	path, err := cty.ParseJSONPointer(ptr, val.Type())
+	if err != nil {
+		return cty.NilVal, err
+	}
+	var wantTy cty.Type
+	switch pty := parent.Type(); {
+	case pty.IsObjectType():
+		wantTy = pty.AttributeType(path[len(path)-1].(cty.GetAttrStep).Name)
+	default:
+		wantTy = pty.ElementType()
+	}
+	v, err := newVal(wantTy)
+	if err != nil {
+		return cty.NilVal, path.NewError(err)
+	}
+	return val.SetPath(path, v)
+}
+
+func patchInsertListElem(val cty.Value, listPath cty.Path, list cty.Value, seg string, newVal func(cty.Type) (cty.Value, error)) (cty.Value, error) {
+	list, marks := list.Unmark()
+	if list.IsNull() || !list.IsKnown() {
+		return cty.NilVal, listPath.NewErrorf("cannot add an element to a null or unknown list")
+	}
+	elems := list.AsValueSlice()
+	idx := len(elems)
+	if seg != "-" {
+		var err error
+		idx, err = strconv.Atoi(seg)
+		if err != nil || idx < 0 || (len(seg) > 1 && seg[0] == '0') {
+			return cty.NilVal, listPath.NewErrorf("invalid index %q: must be a non-negative whole number or \"-\"", seg)
+		}
+		if idx > len(elems) {
+			return cty.NilVal, listPath.NewErrorf("index %d out of range for list with %d elements", idx, len(elems))
+		}
+	}
+
+	v, err := newVal(list.Type().ElementType())
+	if err != nil {
+		return cty.NilVal, listPath.IndexInt(idx).NewError(err)
+	}
+	newElems := make([]cty.Value, 0, len(elems)+1)
+	newElems = append(newElems, elems[:idx]...)
+	newElems = append(newElems, v)
+	newElems = append(newElems, elems[idx:]...)
+	return val.SetPath(listPath, cty.ListVal(newElems).WithMarks(marks))
+}
+
+func patchRemove(val cty.Value, ptr string) (cty.Value, error) {
+	path, err := cty.ParseJSONPointer(ptr, val.Type())
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if len(path) == 0 {
+		return cty.NilVal, path.NewErrorf("cannot remove the top-level value")
+	}
+	if _, err := path.Apply(val); err != nil {
+		return cty.NilVal, path.NewErrorf("no value exists at this location")
+	}
+	if attr, ok := path[len(path)-1].(cty.GetAttrStep); ok {
+		// An object always has all of its attributes, so the best we can
+		// do is set it to null.
+		parent, _ := path[:len(path)-1].Apply(val)
+		return val.SetPath(path, cty.NullVal(parent.Type().AttributeType(attr.Name)))
+	}
+	return val.DeletePath(path)
+}
+
+func patchReplace(val cty.Value, ptr string, raw json.RawMessage) (cty.Value, error) {
+	old, err := patchGet(val, ptr)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	path, _ := cty.ParseJSONPointer(ptr, val.Type()) // already validated by patchGet
+	v, err := decodeAndConvert(raw, old.Type())
+	if err != nil {
+		return cty.NilVal, path.NewError(err)
+	}
+	return val.SetPath(path, v)
+}
+
+func patchMove(val cty.Value, from, to string, moved cty.Value) (cty.Value, error) {
+	if from == to {
+		return val, nil
+	}
+	if strings.HasPrefix(to, from+"/") {
+		path, _ := cty.ParseJSONPointer(from, val.Type()) // already validated by caller
+		return cty.NilVal, path.NewErrorf("cannot move a value into one of its own descendents")
+	}
+	val, err := patchRemove(val, from)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return patchAdd(val, to, func(ty cty.Type) (cty.Value, error) {
+		return convert.Convert(moved, ty)
+	})
+}
+
+func patchTest(val cty.Value, ptr string, raw json.RawMessage) error {
+	got, err := patchGet(val, ptr)
+	if err != nil {
+		return err
+	}
+	path, _ := cty.ParseJSONPointer(ptr, val.Type()) // already validated by patchGet
+	want, err := decodeAndConvert(raw, got.Type())
+	if err != nil {
+		return path.NewError(err)
+	}
+	eq, _ := got.Equals(want).Unmark()
+	if !eq.IsKnown() {
+		return path.NewErrorf("cannot compare with a value that is not yet known")
+	}
+	if eq.False() {
+		return path.NewErrorf("value does not match the expected value")
+	}
+	return nil
+}
+
+// decodeAndConvert decodes the given JSON using its implied type and then
+// converts the result to the given type.
+func decodeAndConvert(raw []byte, ty cty.Type) (cty.Value, error) {
+	impliedTy, err := ImpliedType(raw)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	v, err := Unmarshal(raw, impliedTy)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return convert.Convert(v, ty)
+}
+
+func isJSONNull(raw json.RawMessage) bool {
+	return string(bytes.TrimSpace(raw)) == "null"
+}
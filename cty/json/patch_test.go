@@ -0,0 +1,284 @@
+package json
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+func TestApplyPatch(t *testing.T) {
+	ty := cty.Object(map[string]cty.Type{
+		"name":  cty.String,
+		"port":  cty.Number,
+		"tags":  cty.Map(cty.String),
+		"hosts": cty.List(cty.String),
+		"pair":  cty.Tuple([]cty.Type{cty.String, cty.Bool}),
+	})
+	val := cty.ObjectVal(map[string]cty.Value{
+		"name": cty.StringVal("web"),
+		"port": cty.NumberIntVal(80),
+		"tags": cty.MapVal(map[string]cty.Value{
+			"env": cty.StringVal("prod"),
+		}),
+		"hosts": cty.ListVal([]cty.Value{
+			cty.StringVal("a"),
+			cty.StringVal("b"),
+		}),
+		"pair": cty.TupleVal([]cty.Value{cty.StringVal("x"), cty.True}),
+	})
+
+	tests := map[string]struct {
+		Patch    string
+		Want     cty.Value
+		WantErr  string
+		WantPath cty.Path
+	}{
+		"empty": {
+			`[]`,
+			val,
+			``,
+			nil,
+		},
+		"replace with conversion": {
+			`[{"op":"replace","path":"/port","value":"8080"}]`,
+			mustSetPath(val, cty.GetAttrPath("port"), cty.NumberIntVal(8080)),
+			``,
+			nil,
+		},
+		"add map element": {
+			`[{"op":"add","path":"/tags/team","value":"core"}]`,
+			mustSetPath(val, cty.GetAttrPath("tags").IndexString("team"), cty.StringVal("core")),
+			``,
+			nil,
+		},
+		"insert and append list elements": {
+			`[
+				{"op":"add","path":"/hosts/0","value":"first"},
+				{"op":"add","path":"/hosts/-","value":"last"}
+			]`,
+			mustSetPath(val, cty.GetAttrPath("hosts"), cty.ListVal([]cty.Value{
+				cty.StringVal("first"),
+				cty.StringVal("a"),
+				cty.StringVal("b"),
+				cty.StringVal("last"),
+			})),
+			``,
+			nil,
+		},
+		"remove list element and attribute": {
+			`[
+				{"op":"remove","path":"/hosts/0"},
+				{"op":"remove","path":"/name"}
+			]`,
+			mustSetPath(
+				mustSetPath(val, cty.GetAttrPath("hosts"), cty.ListVal([]cty.Value{cty.StringVal("b")})),
+				cty.GetAttrPath("name"), cty.NullVal(cty.String),
+			),
+			``,
+			nil,
+		},
+		"move and copy": {
+			`[
+				{"op":"copy","from":"/name","path":"/tags/name"},
+				{"op":"move","from":"/hosts/1","path":"/hosts/0"}
+			]`,
+			mustSetPath(
+				mustSetPath(val, cty.GetAttrPath("tags").IndexString("name"), cty.StringVal("web")),
+				cty.GetAttrPath("hosts"), cty.ListVal([]cty.Value{cty.StringVal("b"), cty.StringVal("a")}),
+			),
+			``,
+			nil,
+		},
+		"test passes": {
+			`[{"op":"test","path":"/port","value":80}]`,
+			val,
+			``,
+			nil,
+		},
+		"test fails": {
+			`[{"op":"test","path":"/port","value":81}]`,
+			cty.NilVal,
+			`operation 0 (test) failed: value does not match the expected value`,
+			cty.GetAttrPath("port"),
+		},
+		"inserted value does not convert": {
+			`[{"op":"add","path":"/hosts/-","value":{"a":1}}]`,
+			cty.NilVal,
+			`operation 0 (add) failed: string required, but have object`,
+			cty.GetAttrPath("hosts").IndexInt(2),
+		},
+		"no such attribute": {
+			`[{"op":"replace","path":"/nope","value":1}]`,
+			cty.NilVal,
+			`operation 0 (replace) failed: object has no attribute "nope"`,
+			cty.Path{},
+		},
+		"list index out of range": {
+			`[{"op":"add","path":"/hosts/3","value":"z"}]`,
+			cty.NilVal,
+			`operation 0 (add) failed: index 3 out of range for list with 2 elements`,
+			cty.GetAttrPath("hosts"),
+		},
+		"missing map key": {
+			`[{"op":"remove","path":"/tags/nope"}]`,
+			cty.NilVal,
+			`operation 0 (remove) failed: no value exists at this location`,
+			cty.GetAttrPath("tags").IndexString("nope"),
+		},
+		"tuple element": {
+			`[{"op":"add","path":"/pair/0","value":"y"}]`,
+			cty.NilVal,
+			`operation 0 (add) failed: cannot add an element to a tuple, because that would change its type`,
+			cty.GetAttrPath("pair"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ApplyPatch([]byte(test.Patch), val, ty)
+
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v\nwant error: %s", got, test.WantErr)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				pathErr, ok := err.(cty.PathError)
+				if !ok {
+					t.Fatalf("wrong error type %T", err)
+				}
+				if !pathErr.Path.Equals(test.WantPath) {
+					t.Fatalf("wrong error path\ngot:  %#v\nwant: %#v", pathErr.Path, test.WantPath)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.Want) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	ty := cty.Object(map[string]cty.Type{
+		"name": cty.String,
+		"tags": cty.Map(cty.String),
+		"sub": cty.Object(map[string]cty.Type{
+			"a": cty.Number,
+			"b": cty.Number,
+		}),
+	})
+	val := cty.ObjectVal(map[string]cty.Value{
+		"name": cty.StringVal("web"),
+		"tags": cty.MapVal(map[string]cty.Value{
+			"env":  cty.StringVal("prod"),
+			"team": cty.StringVal("core"),
+		}),
+		"sub": cty.NullVal(cty.Object(map[string]cty.Type{
+			"a": cty.Number,
+			"b": cty.Number,
+		})),
+	})
+
+	tests := map[string]struct {
+		Patch    string
+		Want     cty.Value
+		WantErr  string
+		WantPath cty.Path
+	}{
+		"merge": {
+			`{"name":null,"tags":{"env":"dev","team":null,"new":"x"},"sub":{"a":"1"}}`,
+			cty.ObjectVal(map[string]cty.Value{
+				"name": cty.NullVal(cty.String),
+				"tags": cty.MapVal(map[string]cty.Value{
+					"env": cty.StringVal("dev"),
+					"new": cty.StringVal("x"),
+				}),
+				"sub": cty.ObjectVal(map[string]cty.Value{
+					"a": cty.NumberIntVal(1),
+					"b": cty.NullVal(cty.Number),
+				}),
+			}),
+			``,
+			nil,
+		},
+		"unsupported attribute": {
+			`{"sub":{"c":1}}`,
+			cty.NilVal,
+			`unsupported attribute "c"`,
+			cty.GetAttrPath("sub"),
+		},
+		"wrong type": {
+			`{"tags":["a"]}`,
+			cty.NilVal,
+			`map of string required`,
+			cty.GetAttrPath("tags"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ApplyMergePatch([]byte(test.Patch), val, ty)
+
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v\nwant error: %s", got, test.WantErr)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				pathErr, ok := err.(cty.PathError)
+				if !ok {
+					t.Fatalf("wrong error type %T", err)
+				}
+				if !pathErr.Path.Equals(test.WantPath) {
+					t.Fatalf("wrong error path\ngot:  %#v\nwant: %#v", pathErr.Path, test.WantPath)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.Want) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func mustSetPath(val cty.Value, path cty.Path, newVal cty.Value) cty.Value {
+	ret, err := val.SetPath(path, newVal)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+func TestApplyPatchErrorCause(t *testing.T) {
+	ty := cty.Object(map[string]cty.Type{
+		"port": cty.Number,
+	})
+	val := cty.ObjectVal(map[string]cty.Value{
+		"port": cty.NumberIntVal(80),
+	})
+
+	_, err := ApplyPatch([]byte(`[{"op":"replace","path":"/port","value":{"a":1}}]`), val, ty)
+	if err == nil {
+		t.Fatalf("unexpected success")
+	}
+	var mismatch convert.TypeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("error does not wrap a convert.TypeMismatchError: %#v", err)
+	}
+	if !mismatch.Want.Equals(cty.Number) {
+		t.Errorf("wrong wanted type %#v", mismatch.Want)
+	}
+}
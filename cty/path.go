@@ -3,6 +3,7 @@ package cty
 import (
 	"errors"
 	"fmt"
+	"math/big"
 )
 
 // A Path is a sequence of operations to locate a nested value within a
@@ -27,6 +28,7 @@ type Path []PathStep
 type PathStep interface {
 	pathStepSigil() pathStepImpl
 	Apply(Value) (Value, error)
+	ApplyType(Type) (Type, error)
 }
 
 // embed pathImpl into a struct to declare it a PathStep implementation
@@ -152,6 +154,26 @@ func (p Path) Apply(val Value) (Value, error) {
 	return val, nil
 }
 
+// ApplyType is like Apply except that it works with a type rather than with
+// a value, returning the type of the value that the path would refer to
+// within any value of the given type.
+//
+// A GetAttrStep selects the type of the named attribute of an object type,
+// and an IndexStep selects the element type of a list, map, or set type or
+// the type of the indexed element of a tuple type. Any step applied to
+// DynamicPseudoType produces DynamicPseudoType, since the actual type is not
+// known.
+func (p Path) ApplyType(ty Type) (Type, error) {
+	var err error
+	for i, step := range p {
+		ty, err = step.ApplyType(ty)
+		if err != nil {
+			return NilType, fmt.Errorf("at step %d: %s", i, err)
+		}
+	}
+	return ty, nil
+}
+
 // LastStep applies the given path up to the last step and then returns
 // the resulting value and the final step.
 //
@@ -268,6 +290,73 @@ func (s IndexStep) Apply(val Value) (Value, error) {
 	return val.Index(s.Key), nil
 }
 
+// ApplyType returns the type of the value that would result from indexing
+// a value of the given type with our key value.
+//
+// When indexing a tuple type, the key must be a known whole number that
+// is in range for the tuple, unless all of the tuple's elements have the
+// same type. If the key is unknown and the tuple elements have different
+// types then the result is DynamicPseudoType.
+func (s IndexStep) ApplyType(ty Type) (Type, error) {
+	key, _ := s.Key.Unmark()
+	switch {
+	case ty == DynamicPseudoType:
+		return DynamicPseudoType, nil
+	case ty.IsSetType():
+		return ty.ElementType(), nil
+	case ty.IsListType():
+		if key.Type() != Number && key.Type() != DynamicPseudoType {
+			return NilType, indexKeyTypeError(key)
+		}
+		return ty.ElementType(), nil
+	case ty.IsMapType():
+		if key.Type() != String && key.Type() != DynamicPseudoType {
+			return NilType, indexKeyTypeError(key)
+		}
+		return ty.ElementType(), nil
+	case ty.IsTupleType():
+		if key.Type() != Number && key.Type() != DynamicPseudoType {
+			return NilType, indexKeyTypeError(key)
+		}
+		etys := ty.TupleElementTypes()
+		if !key.IsKnown() {
+			if len(etys) == 0 {
+				return NilType, errors.New("value does not have given index key")
+			}
+			for _, ety := range etys[1:] {
+				if !ety.Equals(etys[0]) {
+					return DynamicPseudoType, nil
+				}
+			}
+			return etys[0], nil
+		}
+		if key.IsNull() {
+			return NilType, errors.New("value does not have given index key")
+		}
+		idx, accuracy := key.AsBigFloat().Int64()
+		if accuracy != big.Exact || idx < 0 || idx >= int64(len(etys)) {
+			return NilType, errors.New("value does not have given index key")
+		}
+		return etys[idx], nil
+	default:
+		return NilType, indexKeyTypeError(key)
+	}
+}
+
+// indexKeyTypeError returns an error describing why the given key cannot be
+// used with a type that isn't appropriate for it, using the same messages
+// that IndexStep.Apply would use.
+func indexKeyTypeError(key Value) error {
+	switch key.Type() {
+	case Number:
+		return errors.New("not a list type")
+	case String:
+		return errors.New("not a map type")
+	default:
+		return errors.New("key value not number or string")
+	}
+}
+
 func (s IndexStep) GoString() string {
 	return fmt.Sprintf("cty.IndexStep{Key:%#v}", s.Key)
 }
@@ -297,6 +386,24 @@ func (s GetAttrStep) Apply(val Value) (Value, error) {
 	return val.GetAttr(s.Name), nil
 }
 
+// ApplyType returns the type of our named attribute in the given type, which
+// must be an object type that has an attribute of that name.
+func (s GetAttrStep) ApplyType(ty Type) (Type, error) {
+	if ty == DynamicPseudoType {
+		return DynamicPseudoType, nil
+	}
+
+	if !ty.IsObjectType() {
+		return NilType, errors.New("not an object type")
+	}
+
+	if !ty.HasAttribute(s.Name) {
+		return NilType, fmt.Errorf("object has no attribute %q", s.Name)
+	}
+
+	return ty.AttributeType(s.Name), nil
+}
+
 func (s GetAttrStep) GoString() string {
 	return fmt.Sprintf("cty.GetAttrStep{Name:%q}", s.Name)
 }
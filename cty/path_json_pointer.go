@@ -0,0 +1,180 @@
+package cty
+
+import (
+	"strconv"
+	"strings"
+)
+
+// JSONPointer returns a JSON Pointer string, as defined in RFC 6901, that
+// refers to the same location as the receiving path.
+//
+// JSON Pointer can represent only attribute names, string map keys, and
+// non-negative integer list or tuple indices, so this method returns an
+// error if the path contains any other kind of step, including steps with
+// unknown keys. The error is a PathError whose path is the prefix of the
+// receiver up to the step that could not be represented.
+//
+// A path alone doesn't say what kind of value each step traverses, so a step
+// into a set whose elements are strings or whole numbers can't be told apart
+// from a map or list step, and is encoded as if it were one. Such steps are
+// detected only by ParseJSONPointer, which uses a type to interpret each
+// segment. Steps into sets of other element types are rejected like any
+// other unrepresentable key.
+//
+// The empty path is represented by the empty string, which is the JSON
+// Pointer referring to the whole document.
+func (p Path) JSONPointer() (string, error) {
+	var buf strings.Builder
+	for i, step := range p {
+		buf.WriteByte('/')
+		switch step := step.(type) {
+		case GetAttrStep:
+			buf.WriteString(escapeJSONPointerSegment(step.Name))
+		case IndexStep:
+			key, _ := step.Key.Unmark()
+			switch {
+			case key == NilVal || key.IsNull():
+				return "", p[:i].NewErrorf("cannot represent null index key in a JSON Pointer")
+			case !key.IsKnown():
+				return "", p[:i].NewErrorf("cannot represent unknown index key in a JSON Pointer")
+			case key.Type() == String:
+				buf.WriteString(escapeJSONPointerSegment(key.AsString()))
+			case key.Type() == Number:
+				bf := key.AsBigFloat()
+				if !bf.IsInt() || bf.Sign() < 0 {
+					return "", p[:i].NewErrorf("cannot represent index %s in a JSON Pointer: must be a non-negative whole number", bf.Text('f', -1))
+				}
+				buf.WriteString(bf.Text('f', 0))
+			default:
+				return "", p[:i].NewErrorf("cannot represent %s index key in a JSON Pointer", key.Type().FriendlyName())
+			}
+		default:
+			// Should never happen because the above is exhaustive for all of
+			// the PathStep implementations in this package.
+			panic("unsupported path step")
+		}
+	}
+	return buf.String(), nil
+}
+
+// ParseJSONPointer parses the given string as a JSON Pointer, as defined in
+// RFC 6901, and returns the equivalent path through a value of the given type.
+//
+// A JSON Pointer segment like "0" could refer either to an object attribute,
+// to a map element, or to a list element, so the given type is used to
+// decide which kind of PathStep to generate for each segment:
+//
+//   - Segments into an object type become GetAttrStep, and the attribute
+//     must be declared in the object type.
+//   - Segments into a map type become IndexStep with a string key.
+//   - Segments into a list or tuple type become IndexStep with a number key,
+//     and must be written as a non-negative whole number with no leading
+//     zeros. For a tuple type, the index must also be in range.
+//
+// A JSON Pointer cannot traverse into a set, a primitive type, a capsule
+// type, or DynamicPseudoType, so segments at those locations are an error.
+// The special "-" segment, which some other specifications use to refer to
+// the position after the end of an array, is also not accepted because it
+// does not correspond to any value.
+//
+// Any errors returned due to segments that cannot be resolved are PathError
+// values whose path refers to the location where resolution failed.
+func ParseJSONPointer(ptr string, ty Type) (Path, error) {
+	path := Path{}
+	if ptr == "" {
+		return path, nil
+	}
+	if ptr[0] != '/' {
+		return nil, path.NewErrorf("a JSON Pointer must either be empty or start with a slash")
+	}
+
+	for _, raw := range strings.Split(ptr[1:], "/") {
+		seg, ok := unescapeJSONPointerSegment(raw)
+		if !ok {
+			return nil, path.NewErrorf("invalid escape sequence in JSON Pointer segment %q", raw)
+		}
+
+		switch {
+		case ty.IsObjectType():
+			if !ty.HasAttribute(seg) {
+				return nil, path.NewErrorf("object has no attribute %q", seg)
+			}
+			path = path.GetAttr(seg)
+			ty = ty.AttributeType(seg)
+		case ty.IsMapType():
+			path = path.IndexString(seg)
+			ty = ty.ElementType()
+		case ty.IsListType() || ty.IsTupleType():
+			idx, ok := parseJSONPointerIndex(seg)
+			if !ok {
+				return nil, path.NewErrorf("invalid index %q: must be a non-negative whole number", seg)
+			}
+			if ty.IsTupleType() {
+				etys := ty.TupleElementTypes()
+				if idx >= len(etys) {
+					return nil, path.NewErrorf("index %d out of range for tuple with %d elements", idx, len(etys))
+				}
+				ty = etys[idx]
+			} else {
+				ty = ty.ElementType()
+			}
+			path = path.IndexInt(idx)
+		case ty.IsSetType():
+			return nil, path.NewErrorf("cannot refer to a set element using a JSON Pointer")
+		case ty == DynamicPseudoType:
+			return nil, path.NewErrorf("cannot resolve JSON Pointer segment %q because the type at this location is not known", seg)
+		default:
+			return nil, path.NewErrorf("cannot traverse into a value of type %s", ty.FriendlyName())
+		}
+	}
+
+	return path, nil
+}
+
+var jsonPointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+func escapeJSONPointerSegment(s string) string {
+	return jsonPointerEscaper.Replace(s)
+}
+
+func unescapeJSONPointerSegment(s string) (string, bool) {
+	if !strings.Contains(s, "~") {
+		return s, true
+	}
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '~' {
+			buf.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			return "", false
+		}
+		i++
+		switch s[i] {
+		case '0':
+			buf.WriteByte('~')
+		case '1':
+			buf.WriteByte('/')
+		default:
+			return "", false
+		}
+	}
+	return buf.String(), true
+}
+
+func parseJSONPointerIndex(s string) (int, bool) {
+	if s == "" || (len(s) > 1 && s[0] == '0') {
+		return 0, false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+	}
+	idx, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, false
+	}
+	return idx, true
+}
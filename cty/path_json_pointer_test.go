@@ -0,0 +1,211 @@
+package cty_test
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestPathJSONPointer(t *testing.T) {
+	tests := []struct {
+		Path    cty.Path
+		Want    string
+		WantErr string
+	}{
+		{
+			cty.Path{},
+			``,
+			``,
+		},
+		{
+			cty.GetAttrPath("servers").IndexInt(0).GetAttr("tags").IndexString("env"),
+			`/servers/0/tags/env`,
+			``,
+		},
+		{
+			cty.GetAttrPath("a/b").IndexString("m~n").IndexString(""),
+			`/a~1b/m~0n/`,
+			``,
+		},
+		{
+			cty.GetAttrPath("a").Index(cty.UnknownVal(cty.Number)),
+			``,
+			`cannot represent unknown index key in a JSON Pointer`,
+		},
+		{
+			cty.GetAttrPath("a").IndexInt(-1),
+			``,
+			`cannot represent index -1 in a JSON Pointer: must be a non-negative whole number`,
+		},
+		{
+			cty.GetAttrPath("a").Index(cty.True),
+			``,
+			`cannot represent bool index key in a JSON Pointer`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Path.String(), func(t *testing.T) {
+			got, err := test.Path.JSONPointer()
+
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %s\nwant error: %s", got, test.WantErr)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				pathErr, ok := err.(cty.PathError)
+				if !ok {
+					t.Fatalf("wrong error type %T", err)
+				}
+				if want := test.Path[:len(test.Path)-1]; !pathErr.Path.Equals(want) {
+					t.Fatalf("wrong error path\ngot:  %#v\nwant: %#v", pathErr.Path, want)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.Want {
+				t.Fatalf("wrong result\ngot:  %s\nwant: %s", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestParseJSONPointer(t *testing.T) {
+	ty := cty.Object(map[string]cty.Type{
+		"0":    cty.String,
+		"a/b":  cty.Map(cty.List(cty.String)),
+		"tup":  cty.Tuple([]cty.Type{cty.String, cty.Set(cty.String)}),
+		"any":  cty.DynamicPseudoType,
+		"name": cty.String,
+	})
+
+	tests := []struct {
+		Ptr      string
+		Want     cty.Path
+		WantErr  string
+		WantPath cty.Path
+	}{
+		{
+			``,
+			cty.Path{},
+			``,
+			nil,
+		},
+		{
+			`/0`,
+			cty.GetAttrPath("0"),
+			``,
+			nil,
+		},
+		{
+			`/a~1b/0/0`,
+			cty.GetAttrPath("a/b").IndexString("0").IndexInt(0),
+			``,
+			nil,
+		},
+		{
+			`/tup/0`,
+			cty.GetAttrPath("tup").IndexInt(0),
+			``,
+			nil,
+		},
+		{
+			`0`,
+			nil,
+			`a JSON Pointer must either be empty or start with a slash`,
+			cty.Path{},
+		},
+		{
+			`/nope`,
+			nil,
+			`object has no attribute "nope"`,
+			cty.Path{},
+		},
+		{
+			`/a~2b`,
+			nil,
+			`invalid escape sequence in JSON Pointer segment "a~2b"`,
+			cty.Path{},
+		},
+		{
+			`/a~1b/x/01`,
+			nil,
+			`invalid index "01": must be a non-negative whole number`,
+			cty.GetAttrPath("a/b").IndexString("x"),
+		},
+		{
+			`/a~1b/x/-`,
+			nil,
+			`invalid index "-": must be a non-negative whole number`,
+			cty.GetAttrPath("a/b").IndexString("x"),
+		},
+		{
+			`/tup/2`,
+			nil,
+			`index 2 out of range for tuple with 2 elements`,
+			cty.GetAttrPath("tup"),
+		},
+		{
+			`/tup/1/0`,
+			nil,
+			`cannot refer to a set element using a JSON Pointer`,
+			cty.GetAttrPath("tup").IndexInt(1),
+		},
+		{
+			`/any/foo`,
+			nil,
+			`cannot resolve JSON Pointer segment "foo" because the type at this location is not known`,
+			cty.GetAttrPath("any"),
+		},
+		{
+			`/name/foo`,
+			nil,
+			`cannot traverse into a value of type string`,
+			cty.GetAttrPath("name"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Ptr, func(t *testing.T) {
+			got, err := cty.ParseJSONPointer(test.Ptr, ty)
+
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v\nwant error: %s", got, test.WantErr)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				pathErr, ok := err.(cty.PathError)
+				if !ok {
+					t.Fatalf("wrong error type %T", err)
+				}
+				if !pathErr.Path.Equals(test.WantPath) {
+					t.Fatalf("wrong error path\ngot:  %#v\nwant: %#v", pathErr.Path, test.WantPath)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.Equals(test.Want) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+
+			// The result should also round-trip back to the same pointer
+			ptr, err := got.JSONPointer()
+			if err != nil {
+				t.Fatalf("unexpected error converting back to pointer: %s", err)
+			}
+			if ptr != test.Ptr {
+				t.Fatalf("wrong round-trip pointer\ngot:  %s\nwant: %s", ptr, test.Ptr)
+			}
+		})
+	}
+}
@@ -0,0 +1,424 @@
+package cty
+
+import (
+	"strings"
+)
+
+// A PathPattern is a sequence of steps that can match zero or more paths
+// within a data structure, in a similar way to how a glob pattern can match
+// zero or more filenames.
+//
+// The steps of a pattern can be either GetAttrStep or IndexStep, which each
+// match exactly the same step in a path, or one of the wildcard steps
+// AnyAttrStep, AnyIndexStep, or AnyDescendantsStep.
+//
+// As with Path, PathPattern has some convenience methods for gradually
+// constructing a pattern, but callers can also construct a slice of
+// PathPatternStep directly.
+type PathPattern []PathPatternStep
+
+// PathPatternStep represents a single step in a PathPattern. PathPatternStep
+// is a closed interface, meaning that the only permitted implementations are
+// those within this package.
+type PathPatternStep interface {
+	pathPatternStepSigil() pathPatternStepImpl
+}
+
+// embed pathPatternStepImpl into a struct to declare it a PathPatternStep
+// implementation
+type pathPatternStepImpl struct{}
+
+func (p pathPatternStepImpl) pathPatternStepSigil() pathPatternStepImpl {
+	return p
+}
+
+func (s GetAttrStep) pathPatternStepSigil() pathPatternStepImpl {
+	return pathPatternStepImpl{}
+}
+
+func (s IndexStep) pathPatternStepSigil() pathPatternStepImpl {
+	return pathPatternStepImpl{}
+}
+
+// AnyAttrStep is a PathPatternStep that matches a GetAttrStep with any
+// attribute name.
+type AnyAttrStep struct {
+	pathPatternStepImpl
+}
+
+// AnyIndexStep is a PathPatternStep that matches an IndexStep with any key,
+// and so matches any element of a list, map, set, or tuple.
+type AnyIndexStep struct {
+	pathPatternStepImpl
+}
+
+// AnyDescendantsStep is a PathPatternStep that matches any sequence of zero
+// or more steps of any kind, similar to "**" in some glob pattern syntaxes.
+type AnyDescendantsStep struct {
+	pathPatternStepImpl
+}
+
+// PathPatternFromPath returns a pattern that matches only the given path.
+func PathPatternFromPath(path Path) PathPattern {
+	ret := make(PathPattern, len(path))
+	for i, step := range path {
+		ret[i] = step.(PathPatternStep)
+	}
+	return ret
+}
+
+// PathPatternFromTypePath returns a pattern that matches the paths of all of
+// the values that the given path through a type refers to, using the
+// conventions of WalkType: each IndexStep with an unknown key becomes an
+// AnyIndexStep, matching any element of a collection.
+//
+// This is useful for describing a path through a type to a user, since the
+// String method of the result writes those steps as [*].
+func PathPatternFromTypePath(path Path) PathPattern {
+	ret := PathPatternFromPath(path)
+	for i, step := range path {
+		if is, ok := step.(IndexStep); ok && !is.Key.IsKnown() {
+			ret[i] = AnyIndexStep{}
+		}
+	}
+	return ret
+}
+
+// GetAttr returns a new PathPattern that is the receiver with a GetAttrStep
+// appended to the end.
+func (p PathPattern) GetAttr(name string) PathPattern {
+	return p.append(GetAttrStep{Name: name})
+}
+
+// Index returns a new PathPattern that is the receiver with an IndexStep
+// appended to the end.
+func (p PathPattern) Index(v Value) PathPattern {
+	return p.append(IndexStep{Key: v})
+}
+
+// AnyAttr returns a new PathPattern that is the receiver with an AnyAttrStep
+// appended to the end.
+func (p PathPattern) AnyAttr() PathPattern {
+	return p.append(AnyAttrStep{})
+}
+
+// AnyIndex returns a new PathPattern that is the receiver with an
+// AnyIndexStep appended to the end.
+func (p PathPattern) AnyIndex() PathPattern {
+	return p.append(AnyIndexStep{})
+}
+
+// AnyDescendants returns a new PathPattern that is the receiver with an
+// AnyDescendantsStep appended to the end.
+func (p PathPattern) AnyDescendants() PathPattern {
+	return p.append(AnyDescendantsStep{})
+}
+
+func (p PathPattern) append(step PathPatternStep) PathPattern {
+	ret := make(PathPattern, len(p)+1)
+	copy(ret, p)
+	ret[len(p)] = step
+	return ret
+}
+
+// Match returns true if the given path matches the receiving pattern.
+func (p PathPattern) Match(path Path) bool {
+	states := p.start()
+	for _, step := range path {
+		states = p.next(states, step)
+		if len(states) == 0 {
+			return false
+		}
+	}
+	return p.accepts(states)
+}
+
+// MatchPrefix returns true if the given path could be the prefix of some
+// path that matches the receiving pattern, including if the given path
+// matches the pattern itself.
+//
+// This can be used to avoid visiting parts of a data structure that cannot
+// possibly contain any matching paths.
+func (p PathPattern) MatchPrefix(path Path) bool {
+	states := p.start()
+	for _, step := range path {
+		states = p.next(states, step)
+		if len(states) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a human-readable representation of the pattern, using the
+// same syntax as Path.String for exact steps and writing the wildcard steps
+// as .*, [*], and ** respectively.
+func (p PathPattern) String() string {
+	var buf strings.Builder
+	for i, step := range p {
+		switch step := step.(type) {
+		case GetAttrStep:
+			s := Path{step}.String()
+			if i > 0 && s[0] != '.' {
+				buf.WriteByte('.')
+			}
+			buf.WriteString(s)
+		case IndexStep:
+			buf.WriteString(Path{step}.String())
+		case AnyAttrStep:
+			if i > 0 {
+				buf.WriteByte('.')
+			}
+			buf.WriteByte('*')
+		case AnyIndexStep:
+			buf.WriteString("[*]")
+		case AnyDescendantsStep:
+			if i > 0 {
+				buf.WriteByte('.')
+			}
+			buf.WriteString("**")
+		}
+	}
+	return buf.String()
+}
+
+// FindPaths returns a PathSet containing the paths of all of the values
+// within the given value that match the receiving pattern.
+//
+// The result can be used with other functions that accept a PathSet, or
+// to test whether a particular location matches without re-evaluating the
+// pattern.
+func (p PathPattern) FindPaths(val Value) PathSet {
+	ret := NewPathSet()
+	WalkMatching(val, p, func(path Path, v Value) error {
+		ret.Add(path.Copy())
+		return nil
+	})
+	return ret
+}
+
+// The matching implementation treats a pattern as a nondeterministic finite
+// automaton where each state is an index into the pattern, with the state
+// equal to the length of the pattern being the accepting state.
+
+// start returns the set of states before consuming any steps.
+func (p PathPattern) start() []int {
+	return p.closure([]int{0})
+}
+
+// closure adds to the given states any that can be reached by skipping
+// over AnyDescendantsStep without consuming a step.
+func (p PathPattern) closure(states []int) []int {
+	for i := 0; i < len(states); i++ {
+		s := states[i]
+		if s < len(p) {
+			if _, ok := p[s].(AnyDescendantsStep); ok && !containsInt(states, s+1) {
+				states = append(states, s+1)
+			}
+		}
+	}
+	return states
+}
+
+// next returns the set of states reachable from the given states by
+// consuming the given step.
+func (p PathPattern) next(states []int, step PathStep) []int {
+	var ret []int
+	for _, s := range states {
+		if s >= len(p) {
+			continue
+		}
+		var to int
+		switch ps := p[s].(type) {
+		case AnyDescendantsStep:
+			to = s
+		case AnyAttrStep:
+			if _, ok := step.(GetAttrStep); !ok {
+				continue
+			}
+			to = s + 1
+		case AnyIndexStep:
+			if _, ok := step.(IndexStep); !ok {
+				continue
+			}
+			to = s + 1
+		case GetAttrStep:
+			if got, ok := step.(GetAttrStep); !ok || got.Name != ps.Name {
+				continue
+			}
+			to = s + 1
+		case IndexStep:
+			got, ok := step.(IndexStep)
+			if !ok {
+				continue
+			}
+			gotKey, _ := got.Key.Unmark()
+			wantKey, _ := ps.Key.Unmark()
+			if !gotKey.RawEquals(wantKey) {
+				continue
+			}
+			to = s + 1
+		default:
+			continue
+		}
+		if !containsInt(ret, to) {
+			ret = append(ret, to)
+		}
+	}
+	return p.closure(ret)
+}
+
+func (p PathPattern) accepts(states []int) bool {
+	return containsInt(states, len(p))
+}
+
+func containsInt(s []int, v int) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// WalkMatching is like Walk except that it calls the given function only for
+// values whose paths match the given pattern, and it does not visit any part
+// of the data structure that cannot contain a matching path.
+//
+// The callback function may halt the walk altogether by returning a non-nil
+// error, which WalkMatching will then return.
+//
+// The path passed to the given function may not be used after that function
+// returns, since its backing array is re-used for other calls.
+func WalkMatching(val Value, pattern PathPattern, cb func(Path, Value) error) error {
+	return walkMatching(nil, val, pattern, pattern.start(), cb)
+}
+
+func walkMatching(path Path, val Value, pattern PathPattern, states []int, cb func(Path, Value) error) error {
+	if pattern.accepts(states) {
+		if err := cb(path, val); err != nil {
+			return err
+		}
+	}
+
+	rawVal, _ := val.Unmark()
+	if rawVal.IsNull() || !rawVal.IsKnown() {
+		return nil
+	}
+
+	ty := rawVal.Type()
+	switch {
+	case ty.IsObjectType():
+		for it := rawVal.ElementIterator(); it.Next(); {
+			nameVal, av := it.Element()
+			step := GetAttrStep{Name: nameVal.AsString()}
+			next := pattern.next(states, step)
+			if len(next) == 0 {
+				continue
+			}
+			if err := walkMatching(append(path, step), av, pattern, next, cb); err != nil {
+				return err
+			}
+		}
+	case rawVal.CanIterateElements():
+		for it := rawVal.ElementIterator(); it.Next(); {
+			kv, ev := it.Element()
+			step := IndexStep{Key: kv}
+			next := pattern.next(states, step)
+			if len(next) == 0 {
+				continue
+			}
+			if err := walkMatching(append(path, step), ev, pattern, next, cb); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TransformMatching is like Transform except that it calls the given
+// function only for values whose paths match the given pattern, and it
+// leaves unchanged any part of the data structure that cannot contain a
+// matching path.
+//
+// As with Transform, nested values are visited before the values that
+// contain them, and it's the responsibility of the given function to
+// preserve expected invariants such as homogenity of element types in
+// collections. Marks on any values that must be reconstructed are preserved.
+//
+// For example, the following marks every attribute named "password" at any
+// depth within the given value:
+//
+//	pattern := cty.PathPattern{}.AnyDescendants().GetAttr("password")
+//	cty.TransformMatching(val, pattern, func(p cty.Path, v cty.Value) (cty.Value, error) {
+//		return v.Mark("sensitive"), nil
+//	})
+//
+// The path passed to the given function may not be used after that function
+// returns, since its backing array is re-used for other calls.
+func TransformMatching(val Value, pattern PathPattern, cb func(Path, Value) (Value, error)) (Value, error) {
+	return transformMatching(nil, val, pattern, pattern.start(), cb)
+}
+
+func transformMatching(path Path, val Value, pattern PathPattern, states []int, cb func(Path, Value) (Value, error)) (Value, error) {
+	newVal := val
+	rawVal, marks := val.Unmark()
+	ty := rawVal.Type()
+
+	if !rawVal.IsNull() && rawVal.IsKnown() && rawVal.CanIterateElements() && rawVal.LengthInt() > 0 {
+		changed := false
+		var elems []Value
+		var attrs map[string]Value
+		if ty.IsObjectType() || ty.IsMapType() {
+			attrs = make(map[string]Value, rawVal.LengthInt())
+		} else {
+			elems = make([]Value, 0, rawVal.LengthInt())
+		}
+
+		for it := rawVal.ElementIterator(); it.Next(); {
+			kv, ev := it.Element()
+			var step PathStep
+			if ty.IsObjectType() {
+				step = GetAttrStep{Name: kv.AsString()}
+			} else {
+				step = IndexStep{Key: kv}
+			}
+			newEv := ev
+			if next := pattern.next(states, step); len(next) != 0 {
+				var err error
+				newEv, err = transformMatching(append(path, step), ev, pattern, next, cb)
+				if err != nil {
+					return DynamicVal, err
+				}
+				if !newEv.RawEquals(ev) {
+					changed = true
+				}
+			}
+			if attrs != nil {
+				attrs[kv.AsString()] = newEv
+			} else {
+				elems = append(elems, newEv)
+			}
+		}
+
+		if changed {
+			switch {
+			case ty.IsObjectType():
+				newVal = ObjectVal(attrs).WithMarks(marks)
+			case ty.IsMapType():
+				newVal = MapVal(attrs).WithMarks(marks)
+			case ty.IsListType():
+				newVal = ListVal(elems).WithMarks(marks)
+			case ty.IsSetType():
+				newVal = SetVal(elems).WithMarks(marks)
+			case ty.IsTupleType():
+				newVal = TupleVal(elems).WithMarks(marks)
+			}
+		}
+	}
+
+	if pattern.accepts(states) {
+		return cb(path, newVal)
+	}
+	return newVal, nil
+}
@@ -0,0 +1,208 @@
+package cty_test
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestPathPatternMatch(t *testing.T) {
+	tests := []struct {
+		Pattern    cty.PathPattern
+		Path       cty.Path
+		Want       bool
+		WantPrefix bool
+	}{
+		{
+			cty.PathPattern{},
+			cty.Path{},
+			true,
+			true,
+		},
+		{
+			cty.PathPattern{}.GetAttr("a"),
+			cty.GetAttrPath("a"),
+			true,
+			true,
+		},
+		{
+			cty.PathPattern{}.GetAttr("a"),
+			cty.GetAttrPath("b"),
+			false,
+			false,
+		},
+		{
+			cty.PathPattern{}.GetAttr("a").AnyIndex().AnyAttr(),
+			cty.GetAttrPath("a").IndexInt(2).GetAttr("b"),
+			true,
+			true,
+		},
+		{
+			cty.PathPattern{}.GetAttr("a").AnyIndex().AnyAttr(),
+			cty.GetAttrPath("a").IndexInt(2),
+			false,
+			true,
+		},
+		{
+			cty.PathPattern{}.AnyAttr(),
+			cty.IndexIntPath(0),
+			false,
+			false,
+		},
+		{
+			cty.PathPattern{}.Index(cty.StringVal("k")),
+			cty.IndexPath(cty.StringVal("k").Mark("m")),
+			true,
+			true,
+		},
+		{
+			cty.PathPattern{}.AnyDescendants().GetAttr("password"),
+			cty.GetAttrPath("password"),
+			true,
+			true,
+		},
+		{
+			cty.PathPattern{}.AnyDescendants().GetAttr("password"),
+			cty.GetAttrPath("a").IndexInt(0).GetAttr("password"),
+			true,
+			true,
+		},
+		{
+			cty.PathPattern{}.AnyDescendants().GetAttr("password"),
+			cty.GetAttrPath("password").GetAttr("x"),
+			false,
+			true,
+		},
+		{
+			cty.PathPattern{}.GetAttr("a").AnyDescendants(),
+			cty.GetAttrPath("a"),
+			true,
+			true,
+		},
+		{
+			cty.PathPattern{}.GetAttr("a").AnyDescendants().AnyIndex(),
+			cty.GetAttrPath("a").GetAttr("b").IndexInt(0),
+			true,
+			true,
+		},
+		{
+			cty.PathPattern{}.GetAttr("a").AnyDescendants().AnyIndex(),
+			cty.GetAttrPath("b"),
+			false,
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Pattern.String()+" "+test.Path.String(), func(t *testing.T) {
+			if got := test.Pattern.Match(test.Path); got != test.Want {
+				t.Errorf("wrong Match result %t; want %t", got, test.Want)
+			}
+			if got := test.Pattern.MatchPrefix(test.Path); got != test.WantPrefix {
+				t.Errorf("wrong MatchPrefix result %t; want %t", got, test.WantPrefix)
+			}
+		})
+	}
+}
+
+func TestPathPatternString(t *testing.T) {
+	got := cty.PathPattern{}.AnyDescendants().GetAttr("a").AnyIndex().AnyAttr().GetAttr("b c").Index(cty.StringVal("d")).String()
+	want := `**.a[*].*."b c"["d"]`
+	if got != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestPathPatternFromTypePath(t *testing.T) {
+	path := cty.GetAttrPath("a").Index(cty.UnknownVal(cty.Number)).IndexInt(0).Index(cty.UnknownVal(cty.String)).GetAttr("b")
+	got := cty.PathPatternFromTypePath(path)
+	if want := `a[*][0][*].b`; got.String() != want {
+		t.Errorf("wrong result\ngot:  %s\nwant: %s", got.String(), want)
+	}
+	if !got.Match(cty.GetAttrPath("a").IndexInt(3).IndexInt(0).IndexString("k").GetAttr("b")) {
+		t.Errorf("pattern does not match a path through a value of the type")
+	}
+}
+
+func TestWalkMatching(t *testing.T) {
+	val := cty.ObjectVal(map[string]cty.Value{
+		"password": cty.StringVal("a"),
+		"users": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":     cty.StringVal("b"),
+				"password": cty.StringVal("c"),
+			}),
+		}),
+		"other": cty.MapVal(map[string]cty.Value{
+			"password": cty.StringVal("not an attribute"),
+		}),
+	})
+
+	pattern := cty.PathPattern{}.AnyDescendants().GetAttr("password")
+	var got []string
+	err := cty.WalkMatching(val, pattern, func(p cty.Path, v cty.Value) error {
+		got = append(got, p.String()+"="+v.AsString())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []string{
+		`password=a`,
+		`users[0].password=c`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("wrong result %d\ngot:  %s\nwant: %s", i, got[i], want[i])
+		}
+	}
+
+	paths := pattern.FindPaths(val)
+	if !paths.Has(cty.GetAttrPath("users").IndexInt(0).GetAttr("password")) {
+		t.Errorf("FindPaths result is missing the nested password")
+	}
+	if paths.Has(cty.GetAttrPath("other").IndexString("password")) {
+		t.Errorf("FindPaths result includes the map element")
+	}
+}
+
+func TestTransformMatching(t *testing.T) {
+	val := cty.ObjectVal(map[string]cty.Value{
+		"password": cty.StringVal("a"),
+		"users": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":     cty.StringVal("b"),
+				"password": cty.StringVal("c"),
+			}),
+		}).Mark("list"),
+		"other": cty.MapVal(map[string]cty.Value{
+			"password": cty.StringVal("not an attribute"),
+		}),
+	})
+
+	got, err := cty.TransformMatching(val, cty.PathPattern{}.AnyDescendants().GetAttr("password"), func(p cty.Path, v cty.Value) (cty.Value, error) {
+		return v.Mark("sensitive"), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := cty.ObjectVal(map[string]cty.Value{
+		"password": cty.StringVal("a").Mark("sensitive"),
+		"users": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"name":     cty.StringVal("b"),
+				"password": cty.StringVal("c").Mark("sensitive"),
+			}),
+		}).Mark("list"),
+		"other": cty.MapVal(map[string]cty.Value{
+			"password": cty.StringVal("not an attribute"),
+		}),
+	})
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
@@ -0,0 +1,252 @@
+package cty
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PathEscapeStyle selects how Path.StringEscaped writes string literals,
+// which appear both in index keys and in attribute names that are not valid
+// identifiers.
+//
+// All of the styles produce strings that ParsePath can accept, so the choice
+// only affects how the result looks to a human reader or to other software
+// that might consume it.
+type PathEscapeStyle rune
+
+const (
+	// PathEscapeGo writes strings as Go-style quoted literals, leaving
+	// printable unicode characters unescaped. This is the style used by
+	// Path.String.
+	PathEscapeGo PathEscapeStyle = 'G'
+
+	// PathEscapeASCII writes strings as Go-style quoted literals with all
+	// non-ASCII characters written as escape sequences, so that the result
+	// is pure ASCII.
+	PathEscapeASCII PathEscapeStyle = 'A'
+
+	// PathEscapeJSON writes strings as JSON string literals.
+	PathEscapeJSON PathEscapeStyle = 'J'
+)
+
+// String returns a human-readable representation of the path, such as
+// servers[0].tags["env"], using PathEscapeGo for any string literals.
+//
+// The result can be parsed back into an equivalent path using ParsePath,
+// as long as every IndexStep key is a string, a number, or an unknown value
+// of either of those types or of DynamicPseudoType. Unknown keys are written
+// as unknown(T), where T uses the syntax of Type.TypeString. Other kinds of
+// key, such as the element values used when describing a path through a set,
+// are written in a form that is readable but that ParsePath will reject.
+//
+// Attribute names that are valid identifiers are written after a period, as
+// in .name, while any other attribute name is written as a quoted string
+// after a period, as in ."not an identifier". The period is omitted for an
+// attribute name that is at the start of the path.
+func (p Path) String() string {
+	return p.StringEscaped(PathEscapeGo)
+}
+
+// StringEscaped is like String except that it allows the caller to select
+// a different style for writing string literals.
+func (p Path) StringEscaped(style PathEscapeStyle) string {
+	var buf strings.Builder
+	for i, step := range p {
+		switch step := step.(type) {
+		case GetAttrStep:
+			if i > 0 {
+				buf.WriteByte('.')
+			}
+			if isTypeSyntaxIdent(step.Name) {
+				buf.WriteString(step.Name)
+			} else {
+				if i == 0 {
+					buf.WriteByte('.')
+				}
+				buf.WriteString(quotePathString(step.Name, style))
+			}
+		case IndexStep:
+			buf.WriteByte('[')
+			key, _ := step.Key.Unmark()
+			switch {
+			case key == NilVal:
+				buf.WriteString("<nil>")
+			case !key.IsKnown() && (key.Type() == String || key.Type() == Number || key.Type() == DynamicPseudoType):
+				buf.WriteString("unknown(")
+				buf.WriteString(key.Type().TypeString())
+				buf.WriteByte(')')
+			case key.IsNull():
+				buf.WriteString("<null>")
+			case key.Type() == String:
+				buf.WriteString(quotePathString(key.AsString(), style))
+			case key.Type() == Number:
+				buf.WriteString(key.AsBigFloat().Text('f', -1))
+			default:
+				fmt.Fprintf(&buf, "<%#v>", key)
+			}
+			buf.WriteByte(']')
+		default:
+			// Should never happen because the above is exhaustive for all of
+			// the PathStep implementations in this package.
+			panic(fmt.Sprintf("unsupported path step %T", step))
+		}
+	}
+	return buf.String()
+}
+
+func quotePathString(s string, style PathEscapeStyle) string {
+	switch style {
+	case PathEscapeASCII:
+		return strconv.QuoteToASCII(s)
+	case PathEscapeJSON:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(s); err != nil {
+			// Should never happen, because all strings are encodable
+			panic(err)
+		}
+		return strings.TrimSuffix(buf.String(), "\n")
+	default:
+		return strconv.Quote(s)
+	}
+}
+
+// PathSyntaxError is the error type returned by ParsePath when the given
+// string is not a valid path.
+type PathSyntaxError struct {
+	// Line and Column are the one-based position of the problem in the
+	// input string, with Column counted in unicode characters.
+	Line, Column int
+
+	// Message describes the problem, without any position information.
+	Message string
+}
+
+func (e PathSyntaxError) Error() string {
+	if e.Line > 1 {
+		return fmt.Sprintf("invalid path at line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("invalid path at column %d: %s", e.Column, e.Message)
+}
+
+// ParsePath parses a string in the syntax produced by Path.String, or by
+// Path.StringEscaped with any escaping style, and returns the path it
+// represents.
+//
+// Index keys may be string literals, number literals, or unknown(T) where
+// T is string, number, or any. The empty string represents the empty path.
+//
+// If the given string is invalid then the returned error is a
+// PathSyntaxError describing the first problem encountered.
+func ParsePath(src string) (Path, error) {
+	p := &typeParser{
+		src: src,
+	}
+	path := Path{}
+
+	p.skipSpace()
+	if p.pos < len(p.src) && p.peek() != '[' && p.peek() != '.' {
+		// A leading attribute name doesn't need a period
+		name := p.ident()
+		if name == "" {
+			return nil, pathSyntaxError(p.errorf("expected an attribute name or index"))
+		}
+		path = path.GetAttr(name)
+	}
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return path, nil
+		}
+
+		switch p.peek() {
+		case '.':
+			p.pos++
+			p.skipSpace()
+			var name string
+			if p.peek() == '"' {
+				var err error
+				name, err = p.attrName()
+				if err != nil {
+					return nil, pathSyntaxError(err)
+				}
+			} else {
+				name = p.ident()
+				if name == "" {
+					return nil, pathSyntaxError(p.errorf("expected an attribute name"))
+				}
+			}
+			path = path.GetAttr(name)
+		case '[':
+			p.pos++
+			key, err := p.parsePathKey()
+			if err != nil {
+				return nil, pathSyntaxError(err)
+			}
+			if err := p.expect(']'); err != nil {
+				return nil, pathSyntaxError(err)
+			}
+			path = path.Index(key)
+		default:
+			return nil, pathSyntaxError(p.errorf("expected '.' or '[', but found %q", p.peekRune()))
+		}
+	}
+}
+
+func (p *typeParser) parsePathKey() (Value, error) {
+	p.skipSpace()
+	start := p.pos
+	switch c := p.peek(); {
+	case c == '"':
+		s, err := p.attrName()
+		if err != nil {
+			return NilVal, err
+		}
+		return StringVal(s), nil
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		for p.pos < len(p.src) && strings.IndexByte("0123456789+-.eE", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		raw := p.src[start:p.pos]
+		v, err := ParseNumberVal(raw)
+		if err != nil {
+			p.pos = start
+			return NilVal, p.errorf("invalid number %q", raw)
+		}
+		return v, nil
+	}
+
+	if p.ident() != "unknown" {
+		p.pos = start
+		return NilVal, p.errorf("expected a string, a number, or unknown(...)")
+	}
+	if err := p.expect('('); err != nil {
+		return NilVal, err
+	}
+	p.skipSpace()
+	tyStart := p.pos
+	ty, err := p.parseType()
+	if err != nil {
+		return NilVal, err
+	}
+	if !(ty.Equals(String) || ty.Equals(Number) || ty.Equals(DynamicPseudoType)) {
+		p.pos = tyStart
+		return NilVal, p.errorf("unknown index key must be string, number, or any")
+	}
+	if err := p.expect(')'); err != nil {
+		return NilVal, err
+	}
+	return UnknownVal(ty), nil
+}
+
+func pathSyntaxError(err error) error {
+	if tErr, ok := err.(TypeSyntaxError); ok {
+		return PathSyntaxError(tErr)
+	}
+	return err
+}
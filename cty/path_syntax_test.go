@@ -0,0 +1,184 @@
+package cty_test
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestPathString(t *testing.T) {
+	tests := []struct {
+		Path  cty.Path
+		Style cty.PathEscapeStyle
+		Want  string
+	}{
+		{
+			cty.Path{},
+			cty.PathEscapeGo,
+			``,
+		},
+		{
+			cty.GetAttrPath("servers").IndexInt(0).GetAttr("tags").IndexString("env"),
+			cty.PathEscapeGo,
+			`servers[0].tags["env"]`,
+		},
+		{
+			cty.IndexIntPath(-2).Index(cty.NumberFloatVal(1.5)),
+			cty.PathEscapeGo,
+			`[-2][1.5]`,
+		},
+		{
+			cty.GetAttrPath("not ident").GetAttr("also not"),
+			cty.PathEscapeGo,
+			`."not ident"."also not"`,
+		},
+		{
+			cty.IndexPath(cty.UnknownVal(cty.String)).Index(cty.UnknownVal(cty.Number)).Index(cty.DynamicVal),
+			cty.PathEscapeGo,
+			`[unknown(string)][unknown(number)][unknown(any)]`,
+		},
+		{
+			cty.IndexPath(cty.StringVal("a").Mark("sensitive")),
+			cty.PathEscapeGo,
+			`["a"]`,
+		},
+		{
+			cty.IndexStringPath("café\n"),
+			cty.PathEscapeGo,
+			`["café\n"]`,
+		},
+		{
+			cty.IndexStringPath("café\n"),
+			cty.PathEscapeASCII,
+			`["caf\u00e9\n"]`,
+		},
+		{
+			cty.IndexStringPath("café\n<>"),
+			cty.PathEscapeJSON,
+			`["café\n<>"]`,
+		},
+		{
+			cty.IndexPath(cty.True),
+			cty.PathEscapeGo,
+			`[<cty.True>]`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Want, func(t *testing.T) {
+			got := test.Path.StringEscaped(test.Style)
+			if got != test.Want {
+				t.Errorf("wrong result\ngot:  %s\nwant: %s", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		Input   string
+		Want    cty.Path
+		WantErr string
+	}{
+		{
+			``,
+			cty.Path{},
+			``,
+		},
+		{
+			`servers[0].tags["env"]`,
+			cty.GetAttrPath("servers").IndexInt(0).GetAttr("tags").IndexString("env"),
+			``,
+		},
+		{
+			`."not ident"[-1.5e2]`,
+			cty.GetAttrPath("not ident").Index(cty.NumberIntVal(-150)),
+			``,
+		},
+		{
+			`[ "café" ] . foo`,
+			cty.IndexStringPath("café").GetAttr("foo"),
+			``,
+		},
+		{
+			`a[unknown(string)][unknown(number)][unknown(any)]`,
+			cty.GetAttrPath("a").Index(cty.UnknownVal(cty.String)).Index(cty.UnknownVal(cty.Number)).Index(cty.DynamicVal),
+			``,
+		},
+		{
+			`a.`,
+			nil,
+			`invalid path at column 3: expected an attribute name`,
+		},
+		{
+			`a[0`,
+			nil,
+			`invalid path at column 4: expected ']', but found end of input`,
+		},
+		{
+			`a[1.2.3]`,
+			nil,
+			`invalid path at column 3: invalid number "1.2.3"`,
+		},
+		{
+			`a[true]`,
+			nil,
+			`invalid path at column 3: expected a string, a number, or unknown(...)`,
+		},
+		{
+			`a[unknown(bool)]`,
+			nil,
+			`invalid path at column 11: unknown index key must be string, number, or any`,
+		},
+		{
+			`a[unknown(strin)]`,
+			nil,
+			`invalid path at column 11: unknown type "strin"`,
+		},
+		{
+			`a b`,
+			nil,
+			`invalid path at column 3: expected '.' or '[', but found 'b'`,
+		},
+		{
+			`["abc]`,
+			nil,
+			`invalid path at column 2: unterminated quoted attribute name`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			got, err := cty.ParsePath(test.Input)
+
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v\nwant error: %s", got, test.WantErr)
+				}
+				if _, ok := err.(cty.PathSyntaxError); !ok {
+					t.Errorf("wrong error type %T", err)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.Equals(test.Want) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+
+			// The canonical string should also round-trip
+			again, err := cty.ParsePath(got.String())
+			if err != nil {
+				t.Fatalf("unexpected error parsing %q: %s", got.String(), err)
+			}
+			if !again.Equals(got) {
+				t.Fatalf("round-trip failed\ngot:  %#v\nwant: %#v", again, got)
+			}
+		})
+	}
+}
@@ -440,3 +440,118 @@ func TestPathEquals(t *testing.T) {
 		})
 	}
 }
+
+func TestPathApplyType(t *testing.T) {
+	tests := []struct {
+		Start   cty.Type
+		Path    cty.Path
+		Want    cty.Type
+		WantErr string
+	}{
+		{
+			cty.String,
+			nil,
+			cty.String,
+			``,
+		},
+		{
+			cty.Object(map[string]cty.Type{
+				"a": cty.List(cty.Map(cty.Set(cty.Bool))),
+			}),
+			cty.GetAttrPath("a").IndexInt(0).IndexString("k").Index(cty.True),
+			cty.Bool,
+			``,
+		},
+		{
+			cty.Tuple([]cty.Type{cty.String, cty.Number}),
+			cty.IndexIntPath(1),
+			cty.Number,
+			``,
+		},
+		{
+			cty.Tuple([]cty.Type{cty.String, cty.Number}),
+			cty.IndexPath(cty.UnknownVal(cty.Number)),
+			cty.DynamicPseudoType,
+			``,
+		},
+		{
+			cty.Tuple([]cty.Type{cty.String, cty.String}),
+			cty.IndexPath(cty.UnknownVal(cty.Number)),
+			cty.String,
+			``,
+		},
+		{
+			cty.List(cty.String),
+			cty.IndexPath(cty.UnknownVal(cty.Number)),
+			cty.String,
+			``,
+		},
+		{
+			cty.DynamicPseudoType,
+			cty.GetAttrPath("a").IndexInt(0),
+			cty.DynamicPseudoType,
+			``,
+		},
+		{
+			cty.Object(map[string]cty.Type{
+				"a": cty.DynamicPseudoType,
+			}),
+			cty.GetAttrPath("a").GetAttr("b"),
+			cty.DynamicPseudoType,
+			``,
+		},
+		{
+			cty.Tuple([]cty.Type{cty.String}),
+			cty.IndexIntPath(1),
+			cty.NilType,
+			`at step 0: value does not have given index key`,
+		},
+		{
+			cty.EmptyObject,
+			cty.GetAttrPath("a"),
+			cty.NilType,
+			`at step 0: object has no attribute "a"`,
+		},
+		{
+			cty.Map(cty.String),
+			cty.IndexIntPath(0),
+			cty.NilType,
+			`at step 0: not a list type`,
+		},
+		{
+			cty.List(cty.String),
+			cty.IndexIntPath(0).GetAttr("a"),
+			cty.NilType,
+			`at step 1: not an object type`,
+		},
+		{
+			cty.String,
+			cty.IndexStringPath("a"),
+			cty.NilType,
+			`at step 0: not a map type`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%#v %s", test.Start, test.Path), func(t *testing.T) {
+			got, err := test.Path.ApplyType(test.Start)
+
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v\nwant error: %s", got, test.WantErr)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.Equals(test.Want) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
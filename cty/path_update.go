@@ -0,0 +1,303 @@
+package cty
+
+// SetPath returns a new value that is the same as the receiver except that
+// the nested value at the given path is replaced with newVal. The receiver
+// itself is not modified.
+//
+// Only the values along the path are rebuilt, so the rest of the data
+// structure is shared between the receiver and the result. Any marks on the
+// values along the path are preserved in the result, while the marks of the
+// replaced value are discarded in favor of any marks on newVal.
+//
+// Because the result must still be a valid value, newVal must have the same
+// type as the value it is replacing. In particular, changing the type of an
+// object attribute or tuple element would change the type of the object or
+// tuple, and changing the type of a collection element would make the
+// collection's elements inconsistent, so these return an error.
+//
+// A final IndexStep into a map may use a key that is not already present,
+// in which case the new element is added to the map. A step into a list or
+// tuple must refer to an existing index, and a step into an object must
+// refer to one of its attributes. A step into a set must use an existing
+// element of the set as its key, and the result is a set with that element
+// replaced by the new value.
+//
+// If the path cannot be resolved then the result is a PathError whose path
+// is the location where resolution failed. If the path is empty then the
+// result is newVal.
+func (val Value) SetPath(path Path, newVal Value) (Value, error) {
+	if len(path) == 0 {
+		return newVal, nil
+	}
+	return updateAtPath(val, path, 0, func(container Value, prefix Path, step PathStep) (Value, error) {
+		return replaceChild(container, prefix, step, newVal, true)
+	})
+}
+
+// DeletePath returns a new value that is the same as the receiver except
+// that the nested value at the given path is removed. The receiver itself
+// is not modified.
+//
+// The final step of the path must refer to an element of a list, map, or
+// set. Removing an element from a list shifts the indices of any subsequent
+// elements down by one. It is not possible to remove an attribute from an
+// object or an element from a tuple, because that would change the type of
+// the object or tuple; set the value to null using SetPath instead.
+//
+// Any marks on the values along the path are preserved in the result.
+//
+// If the path cannot be resolved or does not refer to an existing element
+// then the result is a PathError whose path is the location where resolution
+// failed. DeletePath always returns an error when given an empty path.
+func (val Value) DeletePath(path Path) (Value, error) {
+	if len(path) == 0 {
+		return NilVal, path.NewErrorf("cannot delete the top-level value")
+	}
+	return updateAtPath(val, path, 0, deleteChild)
+}
+
+// updateAtPath is the shared implementation of SetPath and DeletePath, which
+// recursively rebuilds the values along the given path, calling the given
+// function to produce the new version of the value that contains the final
+// step of the path. The given path must not be empty.
+func updateAtPath(val Value, path Path, depth int, final func(container Value, prefix Path, step PathStep) (Value, error)) (Value, error) {
+	prefix := path[:depth]
+	step := path[depth]
+	container, marks := val.Unmark()
+	switch {
+	case container.IsNull():
+		return NilVal, prefix.NewErrorf("cannot update a value nested inside a null value")
+	case !container.IsKnown():
+		return NilVal, prefix.NewErrorf("cannot update a value nested inside an unknown value")
+	}
+
+	if depth == len(path)-1 {
+		ret, err := final(container, prefix, step)
+		if err != nil {
+			return NilVal, err
+		}
+		return ret.WithMarks(marks), nil
+	}
+
+	child, err := childAtStep(container, prefix, step)
+	if err != nil {
+		return NilVal, err
+	}
+	newChild, err := updateAtPath(child, path, depth+1, final)
+	if err != nil {
+		return NilVal, err
+	}
+	ret, err := replaceChild(container, prefix, step, newChild, false)
+	if err != nil {
+		return NilVal, err
+	}
+	return ret.WithMarks(marks), nil
+}
+
+// childAtStep returns the value that the given step refers to within the
+// given known, non-null, and unmarked container value.
+func childAtStep(container Value, prefix Path, step PathStep) (Value, error) {
+	ty := container.Type()
+	switch step := step.(type) {
+	case GetAttrStep:
+		if !ty.IsObjectType() {
+			return NilVal, prefix.NewErrorf("cannot access attribute %q on a value of type %s", step.Name, ty.FriendlyName())
+		}
+		if !ty.HasAttribute(step.Name) {
+			return NilVal, prefix.NewErrorf("object has no attribute %q", step.Name)
+		}
+		return container.GetAttr(step.Name), nil
+	case IndexStep:
+		key, _ := step.Key.Unmark()
+		if ty.IsSetType() {
+			elem, ok := findSetElement(container, key)
+			if !ok {
+				return NilVal, prefix.NewErrorf("set does not contain the requested element")
+			}
+			return elem, nil
+		}
+		if err := checkIndexKey(ty, prefix, key); err != nil {
+			return NilVal, err
+		}
+		if has := container.HasIndex(key); !has.True() {
+			return NilVal, prefix.NewErrorf("value does not have given index key")
+		}
+		return container.Index(key), nil
+	default:
+		// Should never happen because the above is exhaustive for all of
+		// the PathStep implementations in this package.
+		panic("unsupported path step")
+	}
+}
+
+// replaceChild returns a copy of the given known, non-null, and unmarked
+// container value where the element that the given step refers to is
+// replaced by newChild.
+//
+// If allowNew is true then a step into a map may add a new element.
+func replaceChild(container Value, prefix Path, step PathStep, newChild Value, allowNew bool) (Value, error) {
+	ty := container.Type()
+	var wantTy Type
+	old, err := childAtStep(container, prefix, step)
+	switch {
+	case err == nil:
+		wantTy = old.Type()
+	case allowNew && ty.IsMapType():
+		// A missing map key is fine if we're allowed to add new elements,
+		// but we still need to report any problem with the key itself.
+		key, _ := step.(IndexStep).Key.Unmark()
+		if err := checkIndexKey(ty, prefix, key); err != nil {
+			return NilVal, err
+		}
+		wantTy = ty.ElementType()
+	default:
+		return NilVal, err
+	}
+
+	path := append(prefix.Copy(), step)
+	if !newChild.Type().Equals(wantTy) {
+		return NilVal, path.NewErrorf("cannot replace value of type %s with value of type %s", wantTy.FriendlyName(), newChild.Type().FriendlyName())
+	}
+
+	switch {
+	case ty.IsObjectType():
+		name := step.(GetAttrStep).Name
+		attrs := container.AsValueMap()
+		newAttrs := make(map[string]Value, len(attrs))
+		for k, v := range attrs {
+			newAttrs[k] = v
+		}
+		newAttrs[NormalizeString(name)] = newChild
+		return ObjectVal(newAttrs), nil
+	case ty.IsTupleType():
+		idx, _ := step.(IndexStep).Key.Unmark()
+		i, _ := idx.AsBigFloat().Int64()
+		elems := append([]Value(nil), container.AsValueSlice()...)
+		elems[i] = newChild
+		return TupleVal(elems), nil
+	case ty.IsListType():
+		idx, _ := step.(IndexStep).Key.Unmark()
+		i, _ := idx.AsBigFloat().Int64()
+		elems := append([]Value(nil), container.AsValueSlice()...)
+		elems[i] = newChild
+		return ListVal(elems), nil
+	case ty.IsMapType():
+		key, _ := step.(IndexStep).Key.Unmark()
+		elems := container.AsValueMap()
+		newElems := make(map[string]Value, len(elems)+1)
+		for k, v := range elems {
+			newElems[k] = v
+		}
+		newElems[key.AsString()] = newChild
+		return MapVal(newElems), nil
+	case ty.IsSetType():
+		elems := setElementsWithout(container, old)
+		elems = append(elems, newChild)
+		return SetVal(elems), nil
+	default:
+		// Should never happen, because childAtStep would've failed.
+		panic("replaceChild on non-container value")
+	}
+}
+
+// deleteChild returns a copy of the given known, non-null, and unmarked
+// container value where the element that the given step refers to has been
+// removed.
+func deleteChild(container Value, prefix Path, step PathStep) (Value, error) {
+	ty := container.Type()
+	switch {
+	case ty.IsObjectType():
+		return NilVal, prefix.NewErrorf("cannot delete an attribute from an object, because that would change its type")
+	case ty.IsTupleType():
+		return NilVal, prefix.NewErrorf("cannot delete an element from a tuple, because that would change its type")
+	}
+
+	old, err := childAtStep(container, prefix, step)
+	if err != nil {
+		return NilVal, err
+	}
+
+	switch {
+	case ty.IsListType():
+		idx, _ := step.(IndexStep).Key.Unmark()
+		i, _ := idx.AsBigFloat().Int64()
+		elems := container.AsValueSlice()
+		newElems := make([]Value, 0, len(elems)-1)
+		newElems = append(newElems, elems[:i]...)
+		newElems = append(newElems, elems[i+1:]...)
+		if len(newElems) == 0 {
+			return ListValEmpty(ty.ElementType()), nil
+		}
+		return ListVal(newElems), nil
+	case ty.IsMapType():
+		key, _ := step.(IndexStep).Key.Unmark()
+		elems := container.AsValueMap()
+		newElems := make(map[string]Value, len(elems))
+		for k, v := range elems {
+			if k != key.AsString() {
+				newElems[k] = v
+			}
+		}
+		if len(newElems) == 0 {
+			return MapValEmpty(ty.ElementType()), nil
+		}
+		return MapVal(newElems), nil
+	case ty.IsSetType():
+		elems := setElementsWithout(container, old)
+		if len(elems) == 0 {
+			return SetValEmpty(ty.ElementType()), nil
+		}
+		return SetVal(elems), nil
+	default:
+		// Should never happen, because childAtStep would've failed.
+		panic("deleteChild on non-container value")
+	}
+}
+
+func checkIndexKey(ty Type, prefix Path, key Value) error {
+	switch {
+	case key == NilVal || key.IsNull():
+		return prefix.NewErrorf("index key must not be null")
+	case !key.IsKnown():
+		return prefix.NewErrorf("index key must be known")
+	case ty.IsListType() || ty.IsTupleType():
+		if key.Type() != Number {
+			return prefix.NewErrorf("cannot index a %s with a %s key", ty.FriendlyName(), key.Type().FriendlyName())
+		}
+	case ty.IsMapType():
+		if key.Type() != String {
+			return prefix.NewErrorf("cannot index a %s with a %s key", ty.FriendlyName(), key.Type().FriendlyName())
+		}
+	default:
+		return prefix.NewErrorf("cannot index a value of type %s", ty.FriendlyName())
+	}
+	return nil
+}
+
+// findSetElement returns the element of the given known, non-null, and
+// unmarked set value that is identical to the given key.
+func findSetElement(set Value, key Value) (Value, bool) {
+	if key == NilVal {
+		return NilVal, false
+	}
+	for it := set.ElementIterator(); it.Next(); {
+		_, elem := it.Element()
+		if elem.RawEquals(key) {
+			return elem, true
+		}
+	}
+	return NilVal, false
+}
+
+// setElementsWithout returns all of the elements of the given known,
+// non-null, and unmarked set value except for the given element.
+func setElementsWithout(set Value, elem Value) []Value {
+	var ret []Value
+	for it := set.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if !v.RawEquals(elem) {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
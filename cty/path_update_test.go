@@ -0,0 +1,305 @@
+package cty_test
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestValueSetPath(t *testing.T) {
+	tests := map[string]struct {
+		Val      cty.Value
+		Path     cty.Path
+		New      cty.Value
+		Want     cty.Value
+		WantErr  string
+		WantPath cty.Path
+	}{
+		"empty path": {
+			cty.StringVal("a"),
+			cty.Path{},
+			cty.NumberIntVal(1),
+			cty.NumberIntVal(1),
+			``,
+			nil,
+		},
+		"object attribute": {
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+				"b": cty.StringVal("b"),
+			}),
+			cty.GetAttrPath("a"),
+			cty.StringVal("new"),
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("new"),
+				"b": cty.StringVal("b"),
+			}),
+			``,
+			nil,
+		},
+		"nested list in object in tuple": {
+			cty.TupleVal([]cty.Value{
+				cty.True,
+				cty.ObjectVal(map[string]cty.Value{
+					"l": cty.ListVal([]cty.Value{cty.StringVal("x"), cty.StringVal("y")}),
+				}),
+			}),
+			cty.IndexIntPath(1).GetAttr("l").IndexInt(1),
+			cty.StringVal("z"),
+			cty.TupleVal([]cty.Value{
+				cty.True,
+				cty.ObjectVal(map[string]cty.Value{
+					"l": cty.ListVal([]cty.Value{cty.StringVal("x"), cty.StringVal("z")}),
+				}),
+			}),
+			``,
+			nil,
+		},
+		"new map element": {
+			cty.MapVal(map[string]cty.Value{
+				"a": cty.NumberIntVal(1),
+			}),
+			cty.IndexStringPath("b"),
+			cty.NumberIntVal(2),
+			cty.MapVal(map[string]cty.Value{
+				"a": cty.NumberIntVal(1),
+				"b": cty.NumberIntVal(2),
+			}),
+			``,
+			nil,
+		},
+		"set element": {
+			cty.SetVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"n": cty.StringVal("a")}),
+				cty.ObjectVal(map[string]cty.Value{"n": cty.StringVal("b")}),
+			}),
+			cty.IndexPath(cty.ObjectVal(map[string]cty.Value{"n": cty.StringVal("a")})).GetAttr("n"),
+			cty.StringVal("c"),
+			cty.SetVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"n": cty.StringVal("b")}),
+				cty.ObjectVal(map[string]cty.Value{"n": cty.StringVal("c")}),
+			}),
+			``,
+			nil,
+		},
+		"marks on ancestors preserved": {
+			cty.ObjectVal(map[string]cty.Value{
+				"o": cty.ObjectVal(map[string]cty.Value{
+					"a": cty.StringVal("a").Mark("old"),
+					"b": cty.StringVal("b").Mark("sibling"),
+				}).Mark("parent"),
+			}).Mark("root"),
+			cty.GetAttrPath("o").GetAttr("a"),
+			cty.StringVal("new").Mark("new"),
+			cty.ObjectVal(map[string]cty.Value{
+				"o": cty.ObjectVal(map[string]cty.Value{
+					"a": cty.StringVal("new").Mark("new"),
+					"b": cty.StringVal("b").Mark("sibling"),
+				}).Mark("parent"),
+			}).Mark("root"),
+			``,
+			nil,
+		},
+		"wrong attribute type": {
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+			}),
+			cty.GetAttrPath("a"),
+			cty.NumberIntVal(1),
+			cty.NilVal,
+			`cannot replace value of type string with value of type number`,
+			cty.GetAttrPath("a"),
+		},
+		"wrong tuple element type": {
+			cty.TupleVal([]cty.Value{cty.StringVal("a")}),
+			cty.IndexIntPath(0),
+			cty.True,
+			cty.NilVal,
+			`cannot replace value of type string with value of type bool`,
+			cty.IndexIntPath(0),
+		},
+		"no such attribute": {
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.EmptyObjectVal,
+			}),
+			cty.GetAttrPath("a").GetAttr("b"),
+			cty.StringVal("x"),
+			cty.NilVal,
+			`object has no attribute "b"`,
+			cty.GetAttrPath("a"),
+		},
+		"list index out of range": {
+			cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			cty.IndexIntPath(1),
+			cty.StringVal("x"),
+			cty.NilVal,
+			`value does not have given index key`,
+			cty.Path{},
+		},
+		"through null": {
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.NullVal(cty.Map(cty.String)),
+			}),
+			cty.GetAttrPath("a").IndexString("b"),
+			cty.StringVal("x"),
+			cty.NilVal,
+			`cannot update a value nested inside a null value`,
+			cty.GetAttrPath("a"),
+		},
+		"through unknown": {
+			cty.UnknownVal(cty.Map(cty.String)),
+			cty.IndexStringPath("b"),
+			cty.StringVal("x"),
+			cty.NilVal,
+			`cannot update a value nested inside an unknown value`,
+			cty.Path{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := test.Val.SetPath(test.Path, test.New)
+
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v\nwant error: %s", got, test.WantErr)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				pathErr, ok := err.(cty.PathError)
+				if !ok {
+					t.Fatalf("wrong error type %T", err)
+				}
+				if !pathErr.Path.Equals(test.WantPath) {
+					t.Fatalf("wrong error path\ngot:  %#v\nwant: %#v", pathErr.Path, test.WantPath)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.Want) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestValueDeletePath(t *testing.T) {
+	tests := map[string]struct {
+		Val      cty.Value
+		Path     cty.Path
+		Want     cty.Value
+		WantErr  string
+		WantPath cty.Path
+	}{
+		"list element": {
+			cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b"), cty.StringVal("c")}),
+			cty.IndexIntPath(1),
+			cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("c")}),
+			``,
+			nil,
+		},
+		"last list element": {
+			cty.ListVal([]cty.Value{cty.StringVal("a")}).Mark("m"),
+			cty.IndexIntPath(0),
+			cty.ListValEmpty(cty.String).Mark("m"),
+			``,
+			nil,
+		},
+		"nested map element": {
+			cty.ObjectVal(map[string]cty.Value{
+				"m": cty.MapVal(map[string]cty.Value{
+					"a": cty.NumberIntVal(1),
+					"b": cty.NumberIntVal(2),
+				}),
+			}),
+			cty.GetAttrPath("m").IndexString("a"),
+			cty.ObjectVal(map[string]cty.Value{
+				"m": cty.MapVal(map[string]cty.Value{
+					"b": cty.NumberIntVal(2),
+				}),
+			}),
+			``,
+			nil,
+		},
+		"set element": {
+			cty.SetVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			cty.IndexStringPath("a"),
+			cty.SetVal([]cty.Value{cty.StringVal("b")}),
+			``,
+			nil,
+		},
+		"empty path": {
+			cty.StringVal("a"),
+			cty.Path{},
+			cty.NilVal,
+			`cannot delete the top-level value`,
+			cty.Path{},
+		},
+		"object attribute": {
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.StringVal("a"),
+			}),
+			cty.GetAttrPath("a"),
+			cty.NilVal,
+			`cannot delete an attribute from an object, because that would change its type`,
+			cty.Path{},
+		},
+		"tuple element": {
+			cty.ObjectVal(map[string]cty.Value{
+				"t": cty.TupleVal([]cty.Value{cty.StringVal("a")}),
+			}),
+			cty.GetAttrPath("t").IndexInt(0),
+			cty.NilVal,
+			`cannot delete an element from a tuple, because that would change its type`,
+			cty.GetAttrPath("t"),
+		},
+		"missing map key": {
+			cty.MapValEmpty(cty.String),
+			cty.IndexStringPath("a"),
+			cty.NilVal,
+			`value does not have given index key`,
+			cty.Path{},
+		},
+		"wrong key type": {
+			cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			cty.IndexStringPath("a"),
+			cty.NilVal,
+			`cannot index a list of string with a string key`,
+			cty.Path{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := test.Val.DeletePath(test.Path)
+
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v\nwant error: %s", got, test.WantErr)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				pathErr, ok := err.(cty.PathError)
+				if !ok {
+					t.Fatalf("wrong error type %T", err)
+				}
+				if !pathErr.Path.Equals(test.WantPath) {
+					t.Fatalf("wrong error path\ngot:  %#v\nwant: %#v", pathErr.Path, test.WantPath)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.RawEquals(test.Want) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package cty
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TypeString returns a compact string representation of the receiver using
+// the type constraint syntax accepted by ParseType.
+//
+// The result is canonical: two types that are equal always produce the same
+// string, with object attributes sorted by name. For example:
+//
+//	list(object({name=string, port=optional(number)}))
+//
+// DynamicPseudoType is written as "any". Capsule types are written using
+// their friendly name, and so ParseTypeWithCapsules can recover them only if
+// that name is a valid identifier that does not conflict with any of the
+// built-in type keywords.
+//
+// TypeString will panic if called on NilType.
+func (t Type) TypeString() string {
+	var buf strings.Builder
+	writeTypeString(&buf, t)
+	return buf.String()
+}
+
+func writeTypeString(buf *strings.Builder, t Type) {
+	switch impl := t.typeImpl.(type) {
+	case primitiveType:
+		buf.WriteString(impl.FriendlyName(friendlyTypeName))
+	case pseudoTypeDynamic:
+		buf.WriteString("any")
+	case typeList:
+		buf.WriteString("list(")
+		writeTypeString(buf, impl.ElementTypeT)
+		buf.WriteByte(')')
+	case typeMap:
+		buf.WriteString("map(")
+		writeTypeString(buf, impl.ElementTypeT)
+		buf.WriteByte(')')
+	case typeSet:
+		buf.WriteString("set(")
+		writeTypeString(buf, impl.ElementTypeT)
+		buf.WriteByte(')')
+	case typeObject:
+		names := make([]string, 0, len(impl.AttrTypes))
+		for k := range impl.AttrTypes {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+
+		buf.WriteString("object({")
+		for i, name := range names {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			if isTypeSyntaxIdent(name) {
+				buf.WriteString(name)
+			} else {
+				buf.WriteString(strconv.Quote(name))
+			}
+			buf.WriteByte('=')
+			if _, optional := impl.AttrOptional[name]; optional {
+				buf.WriteString("optional(")
+				writeTypeString(buf, impl.AttrTypes[name])
+				buf.WriteByte(')')
+			} else {
+				writeTypeString(buf, impl.AttrTypes[name])
+			}
+		}
+		buf.WriteString("})")
+	case typeTuple:
+		buf.WriteString("tuple([")
+		for i, ety := range impl.ElemTypes {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			writeTypeString(buf, ety)
+		}
+		buf.WriteString("])")
+	case *capsuleType:
+		buf.WriteString(impl.Name)
+	default:
+		// Should never happen, since above should be exhaustive
+		panic("TypeString does not support the given type")
+	}
+}
+
+// TypeSyntaxError is the error type returned by ParseType and
+// ParseTypeWithCapsules when the given string is not a valid type constraint.
+type TypeSyntaxError struct {
+	// Line and Column are the one-based position of the problem in the
+	// input string, with Column counted in unicode characters.
+	Line, Column int
+
+	// Message describes the problem, without any position information.
+	Message string
+}
+
+func (e TypeSyntaxError) Error() string {
+	if e.Line > 1 {
+		return fmt.Sprintf("invalid type at line %d, column %d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("invalid type at column %d: %s", e.Column, e.Message)
+}
+
+// ParseType parses the given string as a type constraint in the syntax
+// produced by Type.TypeString, returning the type it represents.
+//
+// The following forms are accepted, with whitespace allowed between tokens:
+//
+//	bool
+//	number
+//	string
+//	any
+//	list(T)
+//	map(T)
+//	set(T)
+//	tuple([T, T, ...])
+//	object({name=T, other=optional(T), "quoted name"=T, ...})
+//
+// The optional(T) modifier is valid only as the type of an object attribute,
+// and produces an object type as returned by ObjectWithOptionalAttrs.
+//
+// ParseType does not accept capsule types. Use ParseTypeWithCapsules to
+// provide a way to resolve capsule type names.
+//
+// If the given string is invalid then the returned error is a
+// TypeSyntaxError describing the first problem encountered.
+func ParseType(src string) (Type, error) {
+	return ParseTypeWithCapsules(src, nil)
+}
+
+// ParseTypeWithCapsules is like ParseType except that any identifier that
+// is not one of the built-in type keywords is passed to the given function,
+// which can return a capsule type to use in that position. The function
+// should return NilType to indicate that the name is not recognized, in which
+// case parsing fails with an error.
+//
+// The given function may be nil, in which case the result is the same as for
+// ParseType.
+func ParseTypeWithCapsules(src string, capsules func(name string) Type) (Type, error) {
+	p := &typeParser{
+		src:      src,
+		capsules: capsules,
+	}
+	ty, err := p.parseType()
+	if err != nil {
+		return NilType, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return NilType, p.errorf("unexpected extra characters after type")
+	}
+	return ty, nil
+}
+
+type typeParser struct {
+	src      string
+	pos      int
+	capsules func(name string) Type
+}
+
+func (p *typeParser) parseType() (Type, error) {
+	p.skipSpace()
+	start := p.pos
+	name := p.ident()
+	if name == "" {
+		if p.pos >= len(p.src) {
+			return NilType, p.errorf("expected a type")
+		}
+		return NilType, p.errorf("expected a type, but found %q", p.peekRune())
+	}
+
+	switch name {
+	case "bool":
+		return Bool, nil
+	case "number":
+		return Number, nil
+	case "string":
+		return String, nil
+	case "any":
+		return DynamicPseudoType, nil
+	case "list", "map", "set":
+		if err := p.expect('('); err != nil {
+			return NilType, err
+		}
+		ety, err := p.parseType()
+		if err != nil {
+			return NilType, err
+		}
+		if err := p.expect(')'); err != nil {
+			return NilType, err
+		}
+		switch name {
+		case "list":
+			return List(ety), nil
+		case "map":
+			return Map(ety), nil
+		default:
+			return Set(ety), nil
+		}
+	case "tuple":
+		return p.parseTupleArgs()
+	case "object":
+		return p.parseObjectArgs()
+	case "optional":
+		p.pos = start
+		return NilType, p.errorf("optional(...) is allowed only for object attribute types")
+	}
+
+	if p.capsules != nil {
+		if ty := p.capsules(name); ty != NilType {
+			return ty, nil
+		}
+	}
+	p.pos = start
+	return NilType, p.errorf("unknown type %q", name)
+}
+
+func (p *typeParser) parseTupleArgs() (Type, error) {
+	if err := p.expect('('); err != nil {
+		return NilType, err
+	}
+	if err := p.expect('['); err != nil {
+		return NilType, err
+	}
+	var etys []Type
+	for {
+		p.skipSpace()
+		if p.peek() == ']' {
+			p.pos++
+			break
+		}
+		if len(etys) > 0 {
+			if err := p.expect(','); err != nil {
+				return NilType, err
+			}
+			p.skipSpace()
+			if p.peek() == ']' {
+				// Allow a trailing comma
+				p.pos++
+				break
+			}
+		}
+		ety, err := p.parseType()
+		if err != nil {
+			return NilType, err
+		}
+		etys = append(etys, ety)
+	}
+	if err := p.expect(')'); err != nil {
+		return NilType, err
+	}
+	if etys == nil {
+		return EmptyTuple, nil
+	}
+	return Tuple(etys), nil
+}
+
+func (p *typeParser) parseObjectArgs() (Type, error) {
+	if err := p.expect('('); err != nil {
+		return NilType, err
+	}
+	if err := p.expect('{'); err != nil {
+		return NilType, err
+	}
+	atys := make(map[string]Type)
+	var optional []string
+	for {
+		p.skipSpace()
+		if p.peek() == '}' {
+			p.pos++
+			break
+		}
+		if len(atys) > 0 {
+			if err := p.expect(','); err != nil {
+				return NilType, err
+			}
+			p.skipSpace()
+			if p.peek() == '}' {
+				// Allow a trailing comma
+				p.pos++
+				break
+			}
+		}
+
+		nameStart := p.pos
+		name, err := p.attrName()
+		if err != nil {
+			return NilType, err
+		}
+		if _, exists := atys[NormalizeString(name)]; exists {
+			p.pos = nameStart
+			return NilType, p.errorf("duplicate attribute %q", name)
+		}
+		if err := p.expect('='); err != nil {
+			return NilType, err
+		}
+
+		p.skipSpace()
+		isOptional := false
+		if rest := p.src[p.pos:]; strings.HasPrefix(rest, "optional") && !isTypeSyntaxIdentRune(nextRune(rest[len("optional"):])) {
+			p.pos += len("optional")
+			if err := p.expect('('); err != nil {
+				return NilType, err
+			}
+			isOptional = true
+		}
+		aty, err := p.parseType()
+		if err != nil {
+			return NilType, err
+		}
+		if isOptional {
+			p.skipSpace()
+			if p.peek() == ',' {
+				return NilType, p.errorf("optional(...) accepts only one argument")
+			}
+			if err := p.expect(')'); err != nil {
+				return NilType, err
+			}
+			optional = append(optional, name)
+		}
+		atys[NormalizeString(name)] = aty
+	}
+	if err := p.expect(')'); err != nil {
+		return NilType, err
+	}
+	return ObjectWithOptionalAttrs(atys, optional), nil
+}
+
+func (p *typeParser) attrName() (string, error) {
+	p.skipSpace()
+	if p.peek() != '"' {
+		name := p.ident()
+		if name == "" {
+			return "", p.errorf("expected an attribute name")
+		}
+		return name, nil
+	}
+
+	start := p.pos
+	p.pos++ // opening quote
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '"':
+			p.pos++
+			name, err := strconv.Unquote(p.src[start:p.pos])
+			if err != nil {
+				p.pos = start
+				return "", p.errorf("invalid quoted attribute name")
+			}
+			return name, nil
+		default:
+			p.pos++
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated quoted attribute name")
+}
+
+func (p *typeParser) ident() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if !isTypeSyntaxIdentRune(r) || (p.pos == start && !isTypeSyntaxIdentStartRune(r)) {
+			break
+		}
+		p.pos += size
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *typeParser) expect(want byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return p.errorf("expected %q, but found end of input", want)
+	}
+	if p.src[p.pos] != want {
+		return p.errorf("expected %q, but found %q", want, p.peekRune())
+	}
+	p.pos++
+	return nil
+}
+
+func (p *typeParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *typeParser) peek() byte {
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *typeParser) peekRune() rune {
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return r
+}
+
+func (p *typeParser) errorf(f string, args ...any) error {
+	line, col := 1, 1
+	for _, r := range p.src[:p.pos] {
+		if r == '\n' {
+			line++
+			col = 1
+			continue
+		}
+		col++
+	}
+	return TypeSyntaxError{
+		Line:    line,
+		Column:  col,
+		Message: fmt.Sprintf(f, args...),
+	}
+}
+
+func nextRune(s string) rune {
+	if s == "" {
+		return utf8.RuneError
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
+
+func isTypeSyntaxIdentStartRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isTypeSyntaxIdentRune(r rune) bool {
+	return r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isTypeSyntaxIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if !isTypeSyntaxIdentRune(r) || (i == 0 && !isTypeSyntaxIdentStartRune(r)) {
+			return false
+		}
+	}
+	return true
+}
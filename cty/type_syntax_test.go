@@ -0,0 +1,171 @@
+package cty_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestTypeStringRoundTrip(t *testing.T) {
+	capsuleA := cty.Capsule("widget", reflect.TypeOf(0))
+
+	tests := []struct {
+		Type cty.Type
+		Want string
+	}{
+		{cty.Bool, `bool`},
+		{cty.Number, `number`},
+		{cty.String, `string`},
+		{cty.DynamicPseudoType, `any`},
+		{cty.List(cty.String), `list(string)`},
+		{cty.Map(cty.Number), `map(number)`},
+		{cty.Set(cty.List(cty.Bool)), `set(list(bool))`},
+		{cty.EmptyTuple, `tuple([])`},
+		{cty.Tuple([]cty.Type{cty.String, cty.DynamicPseudoType}), `tuple([string, any])`},
+		{cty.EmptyObject, `object({})`},
+		{
+			cty.List(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"name": cty.String,
+				"port": cty.Number,
+			}, []string{"port"})),
+			`list(object({name=string, port=optional(number)}))`,
+		},
+		{
+			cty.Object(map[string]cty.Type{
+				"with space": cty.String,
+				"string":     cty.String,
+			}),
+			`object({string=string, "with space"=string})`,
+		},
+		{capsuleA, `widget`},
+		{cty.Map(capsuleA), `map(widget)`},
+	}
+
+	capsules := func(name string) cty.Type {
+		if name == "widget" {
+			return capsuleA
+		}
+		return cty.NilType
+	}
+
+	for _, test := range tests {
+		t.Run(test.Want, func(t *testing.T) {
+			got := test.Type.TypeString()
+			if got != test.Want {
+				t.Fatalf("wrong string\ngot:  %s\nwant: %s", got, test.Want)
+			}
+
+			ty, err := cty.ParseTypeWithCapsules(got, capsules)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !ty.Equals(test.Type) {
+				t.Fatalf("wrong type\ngot:  %#v\nwant: %#v", ty, test.Type)
+			}
+		})
+	}
+}
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		Input   string
+		Want    cty.Type
+		WantErr string
+	}{
+		{
+			" list ( string ) ",
+			cty.List(cty.String),
+			``,
+		},
+		{
+			"object({\n  a = number,\n  b = optional(string),\n})",
+			cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+				"a": cty.Number,
+				"b": cty.String,
+			}, []string{"b"}),
+			``,
+		},
+		{
+			"tuple([bool,])",
+			cty.Tuple([]cty.Type{cty.Bool}),
+			``,
+		},
+		{
+			"",
+			cty.NilType,
+			`invalid type at column 1: expected a type`,
+		},
+		{
+			"list(strin)",
+			cty.NilType,
+			`invalid type at column 6: unknown type "strin"`,
+		},
+		{
+			"widget",
+			cty.NilType,
+			`invalid type at column 1: unknown type "widget"`,
+		},
+		{
+			"list(string",
+			cty.NilType,
+			`invalid type at column 12: expected ')', but found end of input`,
+		},
+		{
+			"map(string) extra",
+			cty.NilType,
+			`invalid type at column 13: unexpected extra characters after type`,
+		},
+		{
+			"optional(string)",
+			cty.NilType,
+			`invalid type at column 1: optional(...) is allowed only for object attribute types`,
+		},
+		{
+			"object({a=optional(string, \"x\")})",
+			cty.NilType,
+			`invalid type at column 26: optional(...) accepts only one argument`,
+		},
+		{
+			"object({a=string, a=number})",
+			cty.NilType,
+			`invalid type at column 19: duplicate attribute "a"`,
+		},
+		{
+			"object({\n  a = strin\n})",
+			cty.NilType,
+			`invalid type at line 2, column 7: unknown type "strin"`,
+		},
+		{
+			"set(%)",
+			cty.NilType,
+			`invalid type at column 5: expected a type, but found '%'`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Input, func(t *testing.T) {
+			got, err := cty.ParseType(test.Input)
+
+			if test.WantErr != "" {
+				if err == nil {
+					t.Fatalf("unexpected success\ngot: %#v\nwant error: %s", got, test.WantErr)
+				}
+				if _, ok := err.(cty.TypeSyntaxError); !ok {
+					t.Errorf("wrong error type %T", err)
+				}
+				if got := err.Error(); got != test.WantErr {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.WantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !got.Equals(test.Want) {
+				t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
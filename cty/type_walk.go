@@ -0,0 +1,107 @@
+package cty
+
+import (
+	"sort"
+)
+
+// WalkType visits all of the types nested within the given type, calling a
+// given function for each one.
+//
+// For example, given a list of objects the callback would first be called
+// with the list type, then with the object type, and then once for each of
+// the object type's attributes.
+//
+// The path passed to the callback describes how to reach the type from the
+// given type, using the same conventions as Path.ApplyType. Steps into an
+// object attribute are represented by GetAttrStep and steps into a tuple
+// element by an IndexStep with a known number key. Steps into the elements of
+// a list, map, or set are represented by an IndexStep with an unknown key of
+// the appropriate type, which is number, string, or the set's element type
+// respectively, representing that the step applies to any element.
+//
+// The optional argument to the callback is true if the visited type is the
+// type of an attribute that is marked as optional in its containing object
+// type, as created by ObjectWithOptionalAttrs.
+//
+// Object attributes are visited in lexical order by name.
+//
+// The callback function may prevent recursive visits to nested types by
+// returning false. The callback function may halt the walk altogether by
+// returning a non-nil error.
+//
+// The path passed to the given function may not be used after that function
+// returns, since its backing array is re-used for other calls.
+func WalkType(ty Type, cb func(path Path, ty Type, optional bool) (bool, error)) error {
+	var path Path
+	return walkType(path, ty, false, cb)
+}
+
+func walkType(path Path, ty Type, optional bool, cb func(Path, Type, bool) (bool, error)) error {
+	deeper, err := cb(path, ty, optional)
+	if err != nil {
+		return err
+	}
+	if !deeper {
+		return nil
+	}
+
+	switch {
+	case ty.IsObjectType():
+		atys := ty.AttributeTypes()
+		names := make([]string, 0, len(atys))
+		for name := range atys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			path := append(path, GetAttrStep{
+				Name: name,
+			})
+			err := walkType(path, atys[name], ty.AttributeOptional(name), cb)
+			if err != nil {
+				return err
+			}
+		}
+	case ty.IsTupleType():
+		for i, ety := range ty.TupleElementTypes() {
+			path := append(path, IndexStep{
+				Key: NumberIntVal(int64(i)),
+			})
+			err := walkType(path, ety, false, cb)
+			if err != nil {
+				return err
+			}
+		}
+	case ty.IsCollectionType():
+		path := append(path, CollectionElementStep(ty))
+		return walkType(path, ty.ElementType(), false, cb)
+	}
+	return nil
+}
+
+// CollectionElementStep returns the step that WalkType uses to represent
+// all of the elements of the given list, map, or set type, which is an
+// IndexStep whose key is an unknown value of number type, string type, or
+// the set's element type respectively.
+//
+// Since a value can't have a type with optional attributes, the key for a
+// set of objects with optional attributes uses the element type with those
+// attributes made non-optional.
+//
+// CollectionElementStep panics if the given type is not a collection type.
+func CollectionElementStep(ty Type) IndexStep {
+	var keyTy Type
+	switch {
+	case ty.IsListType():
+		keyTy = Number
+	case ty.IsMapType():
+		keyTy = String
+	case ty.IsSetType():
+		keyTy = ty.ElementType().WithoutOptionalAttributesDeep()
+	default:
+		panic("not a collection type")
+	}
+	return IndexStep{
+		Key: UnknownVal(keyTy),
+	}
+}
@@ -0,0 +1,82 @@
+package cty_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestWalkType(t *testing.T) {
+	ty := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"name": cty.String,
+		"tags": cty.Map(cty.String),
+		"servers": cty.List(cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+			"port": cty.Number,
+		}, []string{"port"})),
+		"pair": cty.Tuple([]cty.Type{cty.Bool, cty.Set(cty.String)}),
+	}, []string{"tags"})
+
+	var got []string
+	err := cty.WalkType(ty, func(path cty.Path, nestedTy cty.Type, optional bool) (bool, error) {
+		got = append(got, fmt.Sprintf("%s %s %t", path, nestedTy.TypeString(), optional))
+
+		// The path should always lead to the same type when applied to
+		// the top-level type.
+		pathTy, err := path.ApplyType(ty)
+		if err != nil {
+			t.Errorf("ApplyType(%s) failed: %s", path, err)
+		} else if !pathTy.Equals(nestedTy) {
+			t.Errorf("ApplyType(%s) returned %#v; want %#v", path, pathTy, nestedTy)
+		}
+		return !nestedTy.IsMapType(), nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []string{
+		` object({name=string, pair=tuple([bool, set(string)]), servers=list(object({port=optional(number)})), tags=optional(map(string))}) false`,
+		`name string false`,
+		`pair tuple([bool, set(string)]) false`,
+		`pair[0] bool false`,
+		`pair[1] set(string) false`,
+		`pair[1][unknown(string)] string false`,
+		`servers list(object({port=optional(number)})) false`,
+		`servers[unknown(number)] object({port=optional(number)}) false`,
+		`servers[unknown(number)].port number true`,
+		`tags map(string) true`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("wrong result %d\ngot:  %s\nwant: %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCollectionElementStep(t *testing.T) {
+	optionalObj := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"a": cty.String,
+	}, []string{"a"})
+	tests := []struct {
+		Type    cty.Type
+		WantKey cty.Type
+	}{
+		{cty.List(cty.Bool), cty.Number},
+		{cty.Map(cty.Bool), cty.String},
+		{cty.Set(cty.Bool), cty.Bool},
+		{cty.Set(optionalObj), cty.Object(map[string]cty.Type{"a": cty.String})},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Type.GoString(), func(t *testing.T) {
+			got := cty.CollectionElementStep(test.Type)
+			if got.Key.IsKnown() || !got.Key.Type().Equals(test.WantKey) {
+				t.Errorf("wrong key %#v; want an unknown %#v", got.Key, test.WantKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package cty
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"math/big"
+	"sort"
+)
+
+// HashValue writes a canonical encoding of the given value and its type to
+// the given hash, so that the resulting checksum can be used to identify the
+// value.
+//
+// Unlike Value.Hash, which is intended only for bucketing set elements within
+// a single process, the encoding used by HashValue is stable across versions
+// of this library, and so checksums produced by HashValue can be saved and
+// compared across processes. Any two values that are equal as defined by
+// Value.RawEquals produce the same encoding, and so the same checksum. In
+// particular, numbers are normalized so that numbers that are considered
+// equal produce the same encoding regardless of how they were constructed,
+// and set elements are encoded in a canonical order.
+//
+// Unknown values are encoded distinctly from all known values, including any
+// refinements they have, so that two unknown values produce the same encoding
+// only if they have the same type and the same refinements.
+//
+// HashValue ignores any marks on the given value or on nested values. Use
+// HashValueWithMarks to include marks in the encoding.
+//
+// Capsule-typed values are encoded using the name of their capsule type and
+// the result of the type's HashKey capsule operation, and so their encoding is
+// only as stable as those are. HashKey is designed for bucketing set elements
+// within a single process, so a capsule type whose HashKey result depends on
+// anything other than the encapsulated data, such as a pointer address, will
+// produce checksums that can't be compared across processes. HashValue
+// returns an error if the value contains any capsule-typed values whose type
+// does not implement HashKey at all, since there is no other way to produce a
+// canonical encoding for those.
+func HashValue(h hash.Hash, v Value) error {
+	return HashValueWithMarks(h, v, nil)
+}
+
+// HashValueWithMarks is like HashValue except that it also includes in the
+// encoding the marks on the given value and any nested values, so that two
+// values with the same content but different marks produce different
+// checksums.
+//
+// Marks are arbitrary Go values, so the given function must return a string
+// that canonically represents each mark. The string for each mark is included
+// in the encoding, and so must itself be stable across processes for the
+// checksum to be stable. If the function returns an error then HashValueWithMarks
+// returns that error.
+//
+// If the given function is nil, marks are ignored as for HashValue.
+func HashValueWithMarks(h hash.Hash, v Value, markKey func(mark any) (string, error)) error {
+	e := &valueHashEncoder{markKey: markKey}
+	e.buf.WriteString("cty.v1\x00")
+	e.writeString(v.Type().TypeString())
+	if err := e.encode(nil, v); err != nil {
+		return err
+	}
+	_, err := h.Write(e.buf.Bytes())
+	return err
+}
+
+// valueHashEncoder implements the encoding used by HashValue.
+//
+// The encoding is type-directed and so does not include type information for
+// nested values, since that is implied by the type of the top-level value.
+// Each value starts with a single tag byte, followed by a content whose
+// layout depends on the tag. All lengths and counts are unsigned varints,
+// so that no delimiter is needed between values. This encoding must not
+// change in future versions, since callers rely on it being stable.
+type valueHashEncoder struct {
+	buf     bytes.Buffer
+	markKey func(mark any) (string, error)
+}
+
+const (
+	valueHashTagMarks    = 'M' // count, then each mark key, then the value
+	valueHashTagUnknown  = 'U' // refinements, as described in encodeUnknown
+	valueHashTagNull     = 'N'
+	valueHashTagTrue     = 'T'
+	valueHashTagFalse    = 'F'
+	valueHashTagNumber   = '#' // normalized decimal representation
+	valueHashTagString   = 'S' // length, then UTF-8 bytes
+	valueHashTagSequence = 'L' // count, then each element (list, set, tuple)
+	valueHashTagMapping  = 'O' // count, then each key and value (map, object)
+	valueHashTagCapsule  = 'C' // length, then HashKey result
+)
+
+func (e *valueHashEncoder) encode(path Path, v Value) error {
+	if v.IsMarked() {
+		var marks ValueMarks
+		v, marks = v.Unmark()
+		if e.markKey != nil {
+			keys := make([]string, 0, len(marks))
+			for m := range marks {
+				k, err := e.markKey(m)
+				if err != nil {
+					return path.NewError(err)
+				}
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			e.buf.WriteByte(valueHashTagMarks)
+			e.writeLen(len(keys))
+			for _, k := range keys {
+				e.writeString(k)
+			}
+		}
+	}
+
+	switch {
+	case !v.IsKnown():
+		e.buf.WriteByte(valueHashTagUnknown)
+		e.encodeUnknown(v)
+		return nil
+	case v.IsNull():
+		e.buf.WriteByte(valueHashTagNull)
+		return nil
+	}
+
+	ty := v.Type()
+	switch {
+	case ty == Bool:
+		if v.True() {
+			e.buf.WriteByte(valueHashTagTrue)
+		} else {
+			e.buf.WriteByte(valueHashTagFalse)
+		}
+	case ty == Number:
+		e.buf.WriteByte(valueHashTagNumber)
+		e.writeString(normalizedNumberString(v.AsBigFloat()))
+	case ty == String:
+		e.buf.WriteByte(valueHashTagString)
+		e.writeString(v.AsString())
+	case ty.IsListType() || ty.IsTupleType():
+		e.buf.WriteByte(valueHashTagSequence)
+		e.writeLen(v.LengthInt())
+		for it := v.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			if err := e.encode(append(path, IndexStep{Key: k}), ev); err != nil {
+				return err
+			}
+		}
+	case ty.IsSetType():
+		// Set elements are not stored in a stable order, so we'll encode
+		// each of them separately and then sort the results.
+		elems := make([][]byte, 0, v.LengthInt())
+		for it := v.ElementIterator(); it.Next(); {
+			k, ev := it.Element()
+			sub := &valueHashEncoder{markKey: e.markKey}
+			if err := sub.encode(append(path, IndexStep{Key: k}), ev); err != nil {
+				return err
+			}
+			elems = append(elems, sub.buf.Bytes())
+		}
+		sort.Slice(elems, func(i, j int) bool {
+			return bytes.Compare(elems[i], elems[j]) < 0
+		})
+		e.buf.WriteByte(valueHashTagSequence)
+		e.writeLen(len(elems))
+		for _, elem := range elems {
+			e.buf.Write(elem)
+		}
+	case ty.IsMapType() || ty.IsObjectType():
+		var keys []string
+		if ty.IsObjectType() {
+			for name := range ty.AttributeTypes() {
+				keys = append(keys, name)
+			}
+		} else {
+			for it := v.ElementIterator(); it.Next(); {
+				k, _ := it.Element()
+				keys = append(keys, k.AsString())
+			}
+		}
+		sort.Strings(keys)
+		e.buf.WriteByte(valueHashTagMapping)
+		e.writeLen(len(keys))
+		for _, k := range keys {
+			e.writeString(k)
+			var ev Value
+			var step PathStep
+			if ty.IsObjectType() {
+				ev = v.GetAttr(k)
+				step = GetAttrStep{Name: k}
+			} else {
+				ev = v.Index(StringVal(k))
+				step = IndexStep{Key: StringVal(k)}
+			}
+			if err := e.encode(append(path, step), ev); err != nil {
+				return err
+			}
+		}
+	case ty.IsCapsuleType():
+		ops := ty.CapsuleOps()
+		if ops.HashKey == nil {
+			return path.NewErrorf("cannot hash value of type %s, because it does not implement the HashKey capsule operation", ty.FriendlyName())
+		}
+		e.buf.WriteByte(valueHashTagCapsule)
+		e.writeString(ops.HashKey(v.EncapsulatedValue()))
+	default:
+		// Should never happen, since above should be exhaustive
+		panic(fmt.Sprintf("unsupported type %#v in HashValue", ty))
+	}
+	return nil
+}
+
+// encodeUnknown writes the refinements of the given unknown value: a byte
+// that is 'N' if the value is definitely not null or '?' otherwise, followed
+// by refinements specific to the value's type. For strings that is the known
+// prefix, for numbers it is the lower and upper bounds each followed by a
+// byte that is 'I' if the bound is inclusive or 'E' if it is exclusive, and
+// for collections it is the lower and upper length bounds. Any refinements
+// that aren't set are encoded using their default values, so that they are
+// indistinguishable from explicitly-set refinements that have no effect.
+func (e *valueHashEncoder) encodeUnknown(v Value) {
+	rng := v.Range()
+	if rng.DefinitelyNotNull() {
+		e.buf.WriteByte('N')
+	} else {
+		e.buf.WriteByte('?')
+	}
+
+	ty := v.Type()
+	switch {
+	case ty == String:
+		e.writeString(rng.StringPrefix())
+	case ty == Number:
+		lower, lowerInc := rng.NumberLowerBound()
+		upper, upperInc := rng.NumberUpperBound()
+		for _, bound := range []struct {
+			v         Value
+			inclusive bool
+		}{{lower, lowerInc}, {upper, upperInc}} {
+			e.writeString(normalizedNumberString(bound.v.AsBigFloat()))
+			if bound.inclusive {
+				e.buf.WriteByte('I')
+			} else {
+				e.buf.WriteByte('E')
+			}
+		}
+	case ty.IsCollectionType():
+		e.writeLen(rng.LengthLowerBound())
+		e.writeLen(rng.LengthUpperBound())
+	}
+}
+
+func (e *valueHashEncoder) writeLen(n int) {
+	var buf [binary.MaxVarintLen64]byte
+	l := binary.PutUvarint(buf[:], uint64(n))
+	e.buf.Write(buf[:l])
+}
+
+func (e *valueHashEncoder) writeString(s string) {
+	e.writeLen(len(s))
+	e.buf.WriteString(s)
+}
+
+// normalizedNumberString returns a string representation of the given number
+// that is the same for any two numbers that rawNumberEqual considers equal.
+func normalizedNumberString(f *big.Float) string {
+	if i, acc := f.Int(nil); acc == big.Exact {
+		return i.String()
+	}
+	s := f.Text('f', -1)
+	if s == "-0" {
+		s = "0"
+	}
+	return s
+}
@@ -0,0 +1,153 @@
+package cty_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestHashValue(t *testing.T) {
+	hashOf := func(v cty.Value) string {
+		h := sha256.New()
+		if err := cty.HashValue(h, v); err != nil {
+			t.Fatalf("unexpected error hashing %#v: %s", v, err)
+		}
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	// The encoding is required to be stable across versions, so we'll
+	// test one known checksum to detect any accidental changes.
+	stable := cty.ObjectVal(map[string]cty.Value{
+		"name":  cty.StringVal("web"),
+		"ports": cty.SetVal([]cty.Value{cty.NumberIntVal(80), cty.NumberIntVal(443)}),
+		"tags":  cty.MapVal(map[string]cty.Value{"env": cty.StringVal("prod")}),
+		"extra": cty.TupleVal([]cty.Value{cty.True, cty.NullVal(cty.String), cty.UnknownVal(cty.Number)}),
+	})
+	if got, want := hashOf(stable), "ebd3e6d524f9f76a717ae7b70e07ef9fd334f8f962ce4d514c5ff25564099a44"; got != want {
+		t.Errorf("wrong checksum for stable value\ngot:  %s\nwant: %s", got, want)
+	}
+
+	equal := []struct {
+		A, B cty.Value
+	}{
+		{
+			cty.NumberIntVal(1),
+			cty.NumberFloatVal(1.0),
+		},
+		{
+			cty.MustParseNumberVal("1.50"),
+			cty.NumberFloatVal(1.5),
+		},
+		{
+			cty.NumberVal(new(big.Float).SetPrec(512).SetInt64(10)),
+			cty.MustParseNumberVal("1e1"),
+		},
+		{
+			cty.NumberFloatVal(0),
+			cty.MustParseNumberVal("-0"),
+		},
+		{
+			cty.SetVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			cty.SetVal([]cty.Value{cty.StringVal("b"), cty.StringVal("a")}),
+		},
+		{
+			cty.StringVal("a").Mark("sensitive"),
+			cty.StringVal("a"),
+		},
+		{
+			cty.UnknownVal(cty.String).Refine().StringPrefix("ab").NewValue(),
+			cty.UnknownVal(cty.String).Refine().StringPrefix("ab").NewValue(),
+		},
+	}
+	for _, test := range equal {
+		t.Run(fmt.Sprintf("%#v == %#v", test.A, test.B), func(t *testing.T) {
+			if a, b := hashOf(test.A), hashOf(test.B); a != b {
+				t.Errorf("checksums differ\na: %s\nb: %s", a, b)
+			}
+		})
+	}
+
+	different := []struct {
+		A, B cty.Value
+	}{
+		{
+			cty.StringVal("1"),
+			cty.NumberIntVal(1),
+		},
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			cty.SetVal([]cty.Value{cty.StringVal("a")}),
+		},
+		{
+			cty.StringVal(""),
+			cty.NullVal(cty.String),
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.NullVal(cty.String),
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+		},
+		{
+			cty.UnknownVal(cty.Number).Refine().NumberRangeLowerBound(cty.Zero, true).NewValue(),
+			cty.UnknownVal(cty.Number).Refine().NumberRangeLowerBound(cty.Zero, false).NewValue(),
+		},
+		{
+			cty.UnknownVal(cty.List(cty.String)).Refine().CollectionLengthLowerBound(1).NewValue(),
+			cty.UnknownVal(cty.List(cty.String)),
+		},
+		{
+			cty.TupleVal([]cty.Value{cty.StringVal("ab"), cty.StringVal("")}),
+			cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+		},
+	}
+	for _, test := range different {
+		t.Run(fmt.Sprintf("%#v != %#v", test.A, test.B), func(t *testing.T) {
+			if a, b := hashOf(test.A), hashOf(test.B); a == b {
+				t.Errorf("checksums are equal: %s", a)
+			}
+		})
+	}
+}
+
+func TestHashValueWithMarks(t *testing.T) {
+	hashOf := func(v cty.Value) string {
+		h := sha256.New()
+		err := cty.HashValueWithMarks(h, v, func(mark any) (string, error) {
+			s, ok := mark.(string)
+			if !ok {
+				return "", fmt.Errorf("unsupported mark %#v", mark)
+			}
+			return s, nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error hashing %#v: %s", v, err)
+		}
+		return hex.EncodeToString(h.Sum(nil))
+	}
+
+	plain := cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")})
+	marked := cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b").Mark("sensitive")})
+	if hashOf(plain) == hashOf(marked) {
+		t.Errorf("marks did not change the checksum")
+	}
+	if hashOf(marked) != hashOf(cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b").Mark("sensitive")})) {
+		t.Errorf("equal marked values have different checksums")
+	}
+
+	err := cty.HashValueWithMarks(sha256.New(), cty.ListVal([]cty.Value{cty.True.Mark(1)}), func(mark any) (string, error) {
+		return "", fmt.Errorf("unsupported mark %#v", mark)
+	})
+	if err == nil {
+		t.Fatalf("unexpected success with unsupported mark")
+	}
+	if got, want := err.Error(), "unsupported mark 1"; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}